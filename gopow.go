@@ -2,6 +2,7 @@ package main
 
 import (
 	"os"
+	"strings"
 
 	"github.com/codegangsta/cli"
 	log "github.com/sirupsen/logrus"
@@ -9,6 +10,79 @@ import (
 	"github.com/dhogborg/rtl-gopow/internal/gopow"
 )
 
+// detectFlags are the detection options, used by the detect command and by
+// the render when the events are detected on the fly
+var detectFlags = []cli.Flag{
+	cli.Float64Flag{
+		Name:  "threshold",
+		Value: 10,
+		Usage: "Detection threshold in dB, above the noise floor or absolute",
+	},
+	cli.StringFlag{
+		Name:  "threshold-mode",
+		Value: "floor",
+		Usage: "Threshold relative to the local noise floor or absolute power [floor,absolute]",
+	},
+	cli.Float64Flag{
+		Name:  "floor-percentile",
+		Value: 50,
+		Usage: "Percentile of every bin over time taken as its noise floor",
+	},
+	cli.StringFlag{
+		Name:  "floor-bandwidth",
+		Value: "500k",
+		Usage: "Bandwidth the noise floor is smoothed over, in Hz with optional k/M/G suffix",
+	},
+	cli.IntFlag{
+		Name:  "min-cells",
+		Value: 3,
+		Usage: "Drop events with fewer cells than this",
+	},
+}
+
+// tuningFlags map the tuned frequencies to RF, used by the render and every
+// command so that all axes and exports show RF frequencies
+var tuningFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "freq-offset",
+		Value: "",
+		Usage: "Offset added to the tuned frequencies, e.g. -125M for a 125 MHz upconverter, with optional k/M/G suffix",
+	},
+	cli.BoolFlag{
+		Name:  "freq-invert",
+		Usage: "Converter LO above the RF, the frequency is the offset minus the tuned frequency",
+	},
+	cli.Float64Flag{
+		Name:  "ppm",
+		Value: 0,
+		Usage: "Frequency error of the dongle in ppm, positive when it tunes high",
+	},
+}
+
+// chartFlags style the charts the commands draw in their own image files
+var chartFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "font",
+		Value: "luxisr",
+		Usage: "Chart font, embedded [luxisr,luximr,luxirr] or path to a TTF file",
+	},
+	cli.Float64Flag{
+		Name:  "font-size",
+		Value: 0,
+		Usage: "Chart font size in points, 0 scales with the image size",
+	},
+	cli.StringFlag{
+		Name:  "theme",
+		Value: "dark",
+		Usage: "Chart colors, auto picks a theme that contrasts with the palette [dark,light,auto]",
+	},
+	cli.StringFlag{
+		Name:  "palette",
+		Value: "spectrum",
+		Usage: "Palette of the chart [spectrum,yellow,diverging]",
+	},
+}
+
 func main() {
 	app := cli.NewApp()
 	app.Name = "RTL GoPow"
@@ -75,6 +149,54 @@ func main() {
 			Value: 0,
 			Usage: "Define a manual minimum power (format nn.n)",
 		},
+		cli.Float64Flag{
+			Name:  "min-percentile",
+			Value: 5,
+			Usage: "Percentile of all samples used as minimum power when not set manually",
+		},
+		cli.Float64Flag{
+			Name:  "max-percentile",
+			Value: 99.9,
+			Usage: "Percentile of all samples used as maximum power when not set manually",
+		},
+		cli.BoolFlag{
+			Name:  "normalize-rows",
+			Usage: "Subtract the noise floor of each row to remove gain drift over time",
+		},
+		cli.Float64Flag{
+			Name:  "row-floor-percentile",
+			Value: 50,
+			Usage: "Percentile of a row used as its noise floor with --normalize-rows",
+		},
+		cli.Float64Flag{
+			Name:  "hop-crop",
+			Value: 0,
+			Usage: "Percent of bins at both edges of every hop replaced from the neighboring hops",
+		},
+		cli.IntFlag{
+			Name:  "dc-bins",
+			Value: 0,
+			Usage: "Bins at the center of every hop interpolated over to remove the DC spike",
+		},
+		cli.BoolFlag{
+			Name:  "equalize-hops",
+			Usage: "Offset every hop to a common noise floor to remove the seams between hops",
+		},
+		cli.Float64Flag{
+			Name:  "hop-floor-percentile",
+			Value: 50,
+			Usage: "Percentile of a hop used as its noise floor with --equalize-hops",
+		},
+		cli.StringFlag{
+			Name:  "baseline",
+			Value: "",
+			Usage: "Subtract a per frequency baseline, 'median' of the input or a baseline CSV file",
+		},
+		cli.StringFlag{
+			Name:  "save-baseline",
+			Value: "",
+			Usage: "Write the subtracted baseline to a CSV file",
+		},
 		cli.BoolFlag{
 			Name:  "verbose",
 			Usage: "Enable more verbose output",
@@ -83,11 +205,540 @@ func main() {
 			Name:  "no-annotations",
 			Usage: "Disabled annotations such as time and frequency scales",
 		},
+		cli.StringFlag{
+			Name:  "title",
+			Value: "",
+			Usage: "Title drawn above the image",
+		},
+		cli.StringSliceFlag{
+			Name:  "caption",
+			Value: &cli.StringSlice{},
+			Usage: "Caption line drawn below the title, may be repeated",
+		},
+		cli.StringFlag{
+			Name:  "info",
+			Value: gopow.DefaultInfoTemplate,
+			Usage: "Info box template, lines separated by | with {field} for [" + strings.Join(gopow.InfoFieldNames(), ",") + "]",
+		},
+		cli.StringFlag{
+			Name:  "info-position",
+			Value: "outside",
+			Usage: "Info box position [outside,top-left,top-right,bottom-left,bottom-right]",
+		},
+		cli.StringFlag{
+			Name:  "site",
+			Value: "",
+			Usage: "Site name for the {site} info field",
+		},
+		cli.StringFlag{
+			Name:  "antenna",
+			Value: "",
+			Usage: "Antenna for the {antenna} info field",
+		},
+		cli.StringFlag{
+			Name:  "gain",
+			Value: "",
+			Usage: "Receiver gain for the {gain} info field",
+		},
+		cli.StringFlag{
+			Name:  "font",
+			Value: "luxisr",
+			Usage: "Annotation font, embedded [luxisr,luximr,luxirr] or path to a TTF file",
+		},
+		cli.Float64Flag{
+			Name:  "font-size",
+			Value: 0,
+			Usage: "Annotation font size in points, 0 scales with the image size",
+		},
+		cli.StringFlag{
+			Name:  "font-hinting",
+			Value: "none",
+			Usage: "Annotation font hinting [none,full]",
+		},
+		cli.StringFlag{
+			Name:  "text-color",
+			Value: "",
+			Usage: "Annotation text color, name or hex (#rrggbb or #rrggbbaa), default from the theme",
+		},
+		cli.StringFlag{
+			Name:  "text-style",
+			Value: "outline",
+			Usage: "Annotation text style, outlines and boxes keep text readable on the data [plain,outline,box]",
+		},
+		cli.StringFlag{
+			Name:  "theme",
+			Value: "dark",
+			Usage: "Annotation colors, auto picks a theme that contrasts with the palette [dark,light,auto]",
+		},
+		cli.StringFlag{
+			Name:  "markers",
+			Value: "",
+			Usage: "CSV or JSON file of frequency markers (frequency,bandwidth,label,color)",
+		},
+		cli.StringFlag{
+			Name:  "bandplan",
+			Value: "",
+			Usage: "Comma separated band plans to draw, embedded or CSV files (low,high,label,color) [" + strings.Join(gopow.BandPlanNames(), ",") + "]",
+		},
+		cli.StringFlag{
+			Name:  "channels",
+			Value: "",
+			Usage: "Channel plan for a secondary frequency axis, embedded or CSV file (start,spacing,first,count) [" + strings.Join(gopow.ChannelPlanNames(), ",") + "]",
+		},
+		cli.StringFlag{
+			Name:  "over-color",
+			Value: "",
+			Usage: "Color of cells above the max power, default the top of the palette",
+		},
+		cli.StringFlag{
+			Name:  "under-color",
+			Value: "",
+			Usage: "Color of cells below the min power, default the bottom of the palette",
+		},
+		cli.StringFlag{
+			Name:  "no-data-color",
+			Value: "gray",
+			Usage: "Color of missing and unparseable samples, empty to use the palette",
+		},
+		cli.StringFlag{
+			Name:  "orientation",
+			Value: "vertical",
+			Usage: "Time flowing down with frequency across, or time across with frequency going up [vertical,horizontal]",
+		},
+		cli.BoolFlag{
+			Name:  "newest-first",
+			Usage: "Put the newest rows at the top, or at the left when horizontal",
+		},
+		cli.StringFlag{
+			Name:  "time-labels",
+			Value: "absolute",
+			Usage: "Time axis labels, clock time or time since scan start [absolute,relative]",
+		},
+		cli.IntFlag{
+			Name:  "margin-title",
+			Usage: "Height of the title margin in pixels, default fits the font",
+		},
+		cli.IntFlag{
+			Name:  "margin-freq-axis",
+			Usage: "Height of the frequency axis margin in pixels, default fits the font",
+		},
+		cli.IntFlag{
+			Name:  "margin-channel-axis",
+			Usage: "Height of the channel axis margin in pixels, default fits the font",
+		},
+		cli.IntFlag{
+			Name:  "margin-band-plan",
+			Usage: "Height of the band plan strip in pixels, default fits the font",
+		},
+		cli.IntFlag{
+			Name:  "margin-time-axis",
+			Usage: "Width of the time axis margin in pixels, default fits the font",
+		},
+		cli.IntFlag{
+			Name:  "margin-color-bar",
+			Usage: "Width of the color bar margin in pixels, default fits the font",
+		},
+		cli.IntFlag{
+			Name:  "margin-info-box",
+			Usage: "Height of the info box margin in pixels, default fits the font",
+		},
+		cli.IntFlag{
+			Name:  "margin-occupancy",
+			Usage: "Height of the occupancy chart in pixels, default fits the font",
+		},
+		cli.IntFlag{
+			Name:  "margin-spectrum",
+			Usage: "Height of the spectrum chart in pixels, default fits the font",
+		},
 		cli.StringFlag{
 			Name:  "palette",
-			Usage: "Select the palette for output image. [spectrum,yellow]",
+			Usage: "Select the palette for output image. [spectrum,yellow,diverging]",
 			Value: "spectrum",
 		},
+		cli.StringFlag{
+			Name:  "spectrum",
+			Value: "",
+			Usage: "Draw the max, mean, median and min of every frequency, attached to the waterfall or in its own image [above,below,<file>]",
+		},
+		cli.StringFlag{
+			Name:  "spectrum-csv",
+			Value: "",
+			Usage: "Write the max, mean, median and min of every frequency to this CSV file",
+		},
+		cli.StringFlag{
+			Name:  "spectrum-from",
+			Value: "",
+			Usage: "Start of the spectrum window, \"2006-01-02 15:04:05\" UTC or a duration since the scan start, default the scan start",
+		},
+		cli.StringFlag{
+			Name:  "spectrum-to",
+			Value: "",
+			Usage: "End of the spectrum window, \"2006-01-02 15:04:05\" UTC or a duration since the scan start, default the scan end",
+		},
+		cli.StringFlag{
+			Name:  "occupancy",
+			Value: "",
+			Usage: "Draw the share of time every frequency is over the detection threshold, below the waterfall or in its own image [below,<file>]",
+		},
+		cli.StringFlag{
+			Name:  "events",
+			Value: "",
+			Usage: "Draw boxes around events, detected on the levels before --normalize-rows and --baseline or from an events file [detect,<file>]",
+		},
+		cli.StringFlag{
+			Name:  "event-colors",
+			Value: "power",
+			Usage: "Color event boxes by their peak power or by their class [power,class]",
+		},
+		cli.StringFlag{
+			Name:  "event-class-colors",
+			Value: "",
+			Usage: "Event colors by class as class=color, comma separated",
+		},
+	}
+	app.Flags = append(app.Flags, detectFlags...)
+	app.Flags = append(app.Flags, tuningFlags...)
+
+	app.Commands = []cli.Command{
+		{
+			Name:  "detect",
+			Usage: "Detect transmissions and export them as events",
+			Flags: append([]cli.Flag{
+				cli.StringFlag{
+					Name:  "input,i",
+					Value: "",
+					Usage: "CSV input file generated by rtl_power [required]",
+				},
+				cli.StringFlag{
+					Name:  "output,o",
+					Value: "",
+					Usage: "Events output file, default stdout",
+				},
+				cli.StringFlag{
+					Name:  "format,f",
+					Value: "",
+					Usage: "Events file format, default from the output extension [csv,json]",
+				},
+				cli.BoolFlag{
+					Name:  "verbose",
+					Usage: "Enable more verbose output",
+				},
+			}, append(detectFlags, tuningFlags...)...),
+			Action: func(c *cli.Context) {
+				if c.Bool("verbose") || c.GlobalBool("verbose") {
+					log.SetLevel(log.DebugLevel)
+				} else {
+					log.SetLevel(log.InfoLevel)
+				}
+
+				err := gopow.RunDetect(c)
+				if err != nil {
+					log.WithFields(log.Fields{
+						"error": err.Error(),
+					}).Fatal("detect failed")
+				}
+			},
+		},
+		{
+			Name:  "occupancy",
+			Usage: "Export the share of time every frequency is over the detection threshold",
+			Flags: append([]cli.Flag{
+				cli.StringFlag{
+					Name:  "input,i",
+					Value: "",
+					Usage: "CSV input file generated by rtl_power [required]",
+				},
+				cli.StringFlag{
+					Name:  "output,o",
+					Value: "",
+					Usage: "Occupancy CSV output file, default stdout",
+				},
+				cli.StringFlag{
+					Name:  "chart",
+					Value: "",
+					Usage: "Also draw the occupancy as a chart in this image file",
+				},
+				cli.StringFlag{
+					Name:  "orientation",
+					Value: "vertical",
+					Usage: "Chart with frequency across, or going up to match a horizontal waterfall [vertical,horizontal]",
+				},
+				cli.BoolFlag{
+					Name:  "verbose",
+					Usage: "Enable more verbose output",
+				},
+			}, append(append(detectFlags, chartFlags...), tuningFlags...)...),
+			Action: func(c *cli.Context) {
+				if c.Bool("verbose") || c.GlobalBool("verbose") {
+					log.SetLevel(log.DebugLevel)
+				} else {
+					log.SetLevel(log.InfoLevel)
+				}
+
+				err := gopow.RunOccupancy(c)
+				if err != nil {
+					log.WithFields(log.Fields{
+						"error": err.Error(),
+					}).Fatal("occupancy failed")
+				}
+			},
+		},
+		{
+			Name:  "trace",
+			Usage: "Export the power of frequencies or frequency ranges over time",
+			Flags: append([]cli.Flag{
+				cli.StringFlag{
+					Name:  "input,i",
+					Value: "",
+					Usage: "CSV input file generated by rtl_power [required]",
+				},
+				cli.StringFlag{
+					Name:  "output,o",
+					Value: "",
+					Usage: "Traces CSV output file, default stdout",
+				},
+				cli.StringFlag{
+					Name:  "freq",
+					Value: "",
+					Usage: "Frequencies or ranges to trace, comma separated, e.g. 89.5M,92.2M-92.4M [required]",
+				},
+				cli.StringFlag{
+					Name:  "aggregate",
+					Value: "max",
+					Usage: "Combine the bins of a range by their max or mean power [max,mean]",
+				},
+				cli.StringFlag{
+					Name:  "plot",
+					Value: "",
+					Usage: "Also draw the traces against time in this image file",
+				},
+				cli.IntFlag{
+					Name:  "width",
+					Value: 1000,
+					Usage: "Width of the plot in pixels",
+				},
+				cli.IntFlag{
+					Name:  "height",
+					Value: 400,
+					Usage: "Height of the plot in pixels",
+				},
+				cli.BoolFlag{
+					Name:  "verbose",
+					Usage: "Enable more verbose output",
+				},
+			}, append(chartFlags, tuningFlags...)...),
+			Action: func(c *cli.Context) {
+				if c.Bool("verbose") || c.GlobalBool("verbose") {
+					log.SetLevel(log.DebugLevel)
+				} else {
+					log.SetLevel(log.InfoLevel)
+				}
+
+				err := gopow.RunTrace(c)
+				if err != nil {
+					log.WithFields(log.Fields{
+						"error": err.Error(),
+					}).Fatal("trace failed")
+				}
+			},
+		},
+		{
+			Name:  "diff",
+			Usage: "Render the power difference between two captures",
+			Flags: append([]cli.Flag{
+				cli.StringFlag{
+					Name:  "input,i",
+					Value: "",
+					Usage: "CSV input file generated by rtl_power [required]",
+				},
+				cli.StringFlag{
+					Name:  "compare,c",
+					Value: "",
+					Usage: "CSV file of the capture subtracted from the input [required]",
+				},
+				cli.StringFlag{
+					Name:  "output,o",
+					Value: "",
+					Usage: "Output image file, default <input>.diff.png",
+				},
+				cli.StringFlag{
+					Name:  "align",
+					Value: "relative",
+					Usage: "Pair rows by time since the start of each capture or by clock time where they overlap [relative,overlap]",
+				},
+				cli.Float64Flag{
+					Name:  "range",
+					Usage: "Color scale from -range to +range dB, default from the difference percentiles",
+				},
+				cli.StringFlag{
+					Name:  "title",
+					Value: "",
+					Usage: "Title of the image, default the compared files",
+				},
+				cli.StringFlag{
+					Name:  "stats",
+					Value: "",
+					Usage: "Statistics CSV of the frequencies that differ most, default stdout",
+				},
+				cli.IntFlag{
+					Name:  "top",
+					Value: 10,
+					Usage: "Frequencies in the statistics, 0 for all",
+				},
+				cli.BoolFlag{
+					Name:  "verbose",
+					Usage: "Enable more verbose output",
+				},
+			}, tuningFlags...),
+			Action: func(c *cli.Context) {
+				if c.Bool("verbose") || c.GlobalBool("verbose") {
+					log.SetLevel(log.DebugLevel)
+				} else {
+					log.SetLevel(log.InfoLevel)
+				}
+
+				pow, err := gopow.NewDiff(c)
+				if err != nil {
+					log.WithFields(log.Fields{
+						"error": err.Error(),
+					}).Fatal("load failed")
+					return
+				}
+
+				err = pow.Render()
+				if err != nil {
+					log.WithFields(log.Fields{
+						"error": err.Error(),
+					}).Fatal("render failed")
+					return
+				}
+
+				err = pow.Write()
+				if err != nil {
+					log.WithFields(log.Fields{
+						"error": err.Error(),
+					}).Fatal("write failed")
+				}
+			},
+		},
+		{
+			Name:  "baseline",
+			Usage: "Build the per frequency statistics of reference captures as a baseline",
+			Flags: append([]cli.Flag{
+				cli.StringFlag{
+					Name:  "input,i",
+					Value: "",
+					Usage: "CSV input files generated by rtl_power, comma separated [required]",
+				},
+				cli.StringFlag{
+					Name:  "output,o",
+					Value: "",
+					Usage: "Baseline CSV output file, default stdout",
+				},
+				cli.BoolFlag{
+					Name:  "verbose",
+					Usage: "Enable more verbose output",
+				},
+			}, tuningFlags...),
+			Action: func(c *cli.Context) {
+				if c.Bool("verbose") || c.GlobalBool("verbose") {
+					log.SetLevel(log.DebugLevel)
+				} else {
+					log.SetLevel(log.InfoLevel)
+				}
+
+				err := gopow.RunBaseline(c)
+				if err != nil {
+					log.WithFields(log.Fields{
+						"error": err.Error(),
+					}).Fatal("baseline failed")
+				}
+			},
+		},
+		{
+			Name:  "anomaly",
+			Usage: "Render a capture with the cells over a baseline highlighted and export them as events",
+			Flags: append([]cli.Flag{
+				cli.StringFlag{
+					Name:  "input,i",
+					Value: "",
+					Usage: "CSV input file generated by rtl_power [required]",
+				},
+				cli.StringFlag{
+					Name:  "baseline,b",
+					Value: "",
+					Usage: "Baseline CSV file written by the baseline command [required]",
+				},
+				cli.StringFlag{
+					Name:  "output,o",
+					Value: "",
+					Usage: "Output image file, default <input>.anomaly.png",
+				},
+				cli.Float64Flag{
+					Name:  "sigma",
+					Value: 3,
+					Usage: "Flag cells this many standard deviations over the baseline median, 0 disables",
+				},
+				cli.Float64Flag{
+					Name:  "db",
+					Usage: "Flag cells this many dB over the baseline median, replaces the default sigma when set alone",
+				},
+				cli.IntFlag{
+					Name:  "min-cells",
+					Value: 3,
+					Usage: "Drop anomalies with fewer cells than this",
+				},
+				cli.StringFlag{
+					Name:  "events",
+					Value: "",
+					Usage: "Anomaly events output file, default stdout",
+				},
+				cli.StringFlag{
+					Name:  "format,f",
+					Value: "",
+					Usage: "Events file format, default from the events extension [csv,json]",
+				},
+				cli.StringFlag{
+					Name:  "title",
+					Value: "",
+					Usage: "Title of the image",
+				},
+				cli.BoolFlag{
+					Name:  "verbose",
+					Usage: "Enable more verbose output",
+				},
+			}, tuningFlags...),
+			Action: func(c *cli.Context) {
+				if c.Bool("verbose") || c.GlobalBool("verbose") {
+					log.SetLevel(log.DebugLevel)
+				} else {
+					log.SetLevel(log.InfoLevel)
+				}
+
+				pow, err := gopow.NewAnomaly(c)
+				if err != nil {
+					log.WithFields(log.Fields{
+						"error": err.Error(),
+					}).Fatal("load failed")
+					return
+				}
+
+				err = pow.Render()
+				if err != nil {
+					log.WithFields(log.Fields{
+						"error": err.Error(),
+					}).Fatal("render failed")
+					return
+				}
+
+				err = pow.Write()
+				if err != nil {
+					log.WithFields(log.Fields{
+						"error": err.Error(),
+					}).Fatal("write failed")
+				}
+			},
+		},
 	}
 
 	app.Run(os.Args)
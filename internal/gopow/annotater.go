@@ -3,41 +3,105 @@ package gopow
 import (
 	"fmt"
 	"image"
+	"image/color"
+	"image/draw"
 	"math"
+	"path"
 	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/golang/freetype"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/image/font"
-
-	"github.com/dhogborg/rtl-gopow/internal/resources"
 )
 
 // font configuration
 const (
-	dpi      float64 = 72
-	fontfile string  = "resources/fonts/luxisr.ttf"
-	hinting  string  = "none"
-	size     float64 = 15
-	spacing  float64 = 1.1
+	dpi     float64 = 72
+	spacing float64 = 1.1
+)
+
+// color bar placement inside its margin
+const (
+	colorBarWidth int = 20
+	colorBarInset int = 10
 )
 
+// title font size relative to the annotation font
+const titleScale float64 = 1.5
+
+// AnnotatorConfig holds the annotation options
+type AnnotatorConfig struct {
+	Font       FontConfig
+	TimeLabels string // TimeLabelsAbsolute or TimeLabelsRelative
+
+	Theme     string // ThemeAuto, ThemeDark or ThemeLight
+	TextStyle string // TextPlain, TextOutline or TextBox
+
+	EventColors string                 // EventColorPower or EventColorClass
+	ClassColors map[string]color.Color // event colors by class
+
+	Title        string
+	Captions     []string
+	Info         string // info box template, see DefaultInfoTemplate
+	InfoPosition string // InfoOutside or a corner of the data
+	Meta         InfoMeta
+}
+
 type Annotator struct {
-	image *image.RGBA
-	table *TableComplex
+	image   *image.RGBA
+	table   *TableComplex
+	palette Palette
+	layout  *Layout
+
+	timeLabels   string
+	eventColors  string
+	classColors  map[string]color.Color
+	title        string
+	captions     []string
+	info         string
+	infoPosition string
+	meta         InfoMeta
 
-	context *freetype.Context
+	context   *freetype.Context
+	measure   func(string) int // width of a string in pixels
+	size      float64          // font size in points
+	fg        color.Color      // text and guideline color
+	halo      color.Color      // text outline and box color
+	textStyle string
 }
 
-func NewAnnotator(img *image.RGBA, table *TableComplex) (*Annotator, error) {
+func NewAnnotator(img *image.RGBA, table *TableComplex, palette Palette, layout *Layout, conf AnnotatorConfig) (*Annotator, error) {
 
 	a := &Annotator{
-		image: img,
-		table: table,
+		image:   img,
+		table:   table,
+		palette: palette,
+		layout:  layout,
+
+		timeLabels:   conf.TimeLabels,
+		eventColors:  conf.EventColors,
+		classColors:  conf.ClassColors,
+		title:        conf.Title,
+		captions:     conf.Captions,
+		info:         conf.Info,
+		infoPosition: conf.InfoPosition,
+		meta:         conf.Meta,
 	}
 
-	err := a.init()
+	theme, err := ResolveTheme(conf.Theme, palette, table)
+	if err != nil {
+		return nil, err
+	}
+
+	a.halo = theme.Halo
+	a.textStyle = conf.TextStyle
+
+	if conf.Font.Color == nil {
+		conf.Font.Color = theme.Text
+	}
+
+	err = a.init(conf.Font)
 	if err != nil {
 		return nil, err
 	}
@@ -46,32 +110,31 @@ func NewAnnotator(img *image.RGBA, table *TableComplex) (*Annotator, error) {
 
 }
 
-func (a *Annotator) init() error {
+func (a *Annotator) init(conf FontConfig) error {
 
 	// load the font
-	fontBytes, err := resources.Asset(fontfile)
+	ttf, err := conf.load()
 	if err != nil {
 		return err
 	}
 
-	luxisr, err := freetype.ParseFont(fontBytes)
+	// Initialize the context.
+	a.size = conf.SizeFor(a.table)
+	a.fg = conf.Color
+
+	a.measure, err = conf.Measurer(a.size)
 	if err != nil {
 		return err
 	}
 
-	// Initialize the context.
-	fg := image.White
-
 	a.context = freetype.NewContext()
 	a.context.SetDPI(dpi)
-	a.context.SetFont(luxisr)
-	a.context.SetFontSize(size)
+	a.context.SetFont(ttf)
+	a.context.SetFontSize(a.size)
 
-	a.context.SetClip(a.image.Bounds())
 	a.context.SetDst(a.image)
-	a.context.SetSrc(fg)
 
-	switch hinting {
+	switch conf.Hinting {
 	default:
 		a.context.SetHinting(font.HintingNone)
 	case "full":
@@ -83,39 +146,70 @@ func (a *Annotator) init() error {
 
 func (a *Annotator) DrawXScale() error {
 
+	if a.layout.FreqAxisArea().Empty() {
+		return nil
+	}
+
 	log.WithFields(log.Fields{
 		"hzHigh": humanize.SI(a.table.HzHigh, "Hz"),
 		"hzLow":  humanize.SI(a.table.HzLow, "Hz"),
 	}).Debug("annotate X scale")
 
-	// how many samples?
-	count := int(math.Floor(float64(a.table.Bins) / float64(350)))
+	// room for one label every widest label plus some air, or every few
+	// lines when the labels are stacked
+	width := a.measure("000.000 MHz") + int(2*a.size)
+	if a.layout.Horizontal() {
+		width = int(3 * a.size)
+	}
+	count := a.table.Bins / width
+	if count < 1 {
+		count = 1
+	}
 
-	hzPerLabel := float64(a.table.HzHigh-a.table.HzLow) / float64(count)
-	pxPerLabel := int(math.Floor(float64(a.table.Bins) / float64(count)))
+	ticks := freqTicks(a.table, count)
 
 	log.WithFields(log.Fields{
-		"labels":     count,
-		"hzPerLabel": humanize.SI(hzPerLabel, "Hz"),
-		"pxPerLabel": pxPerLabel,
+		"labels": count,
+		"ticks":  len(ticks),
 	}).Debug("annotate X scale")
 
-	for si := 0; si < count; si++ {
+	area := a.layout.FreqAxisArea()
 
-		hz := a.table.HzLow + (float64(si) * hzPerLabel)
-		px := si * pxPerLabel
+	for _, t := range ticks {
 
-		fract, suffix := humanize.ComputeSI(hz)
-		str := fmt.Sprintf("%0.2f %sHz", fract, suffix)
+		px := a.layout.FreqPos(t.offset)
 
-		// draw a guideline on the exact frequency
-		for i := 0; i < 30; i++ {
-			a.image.Set(px, i, image.White)
+		if a.layout.Horizontal() {
+			// short minor ticks and guidelines left of the data, labels
+			// above their guideline
+			from := area.Min.X
+			if !t.major {
+				from = area.Max.X - area.Dx()/8
+			}
+			for x := from; x < area.Max.X; x++ {
+				a.image.Set(x, px, a.fg)
+			}
+			if t.major {
+				a.drawText(t.label, image.Pt(area.Min.X+3, px-3), area)
+			}
+			continue
+		}
+
+		if !t.major {
+			// short minor tick next to the data
+			for y := area.Max.Y - area.Dy()/5; y < area.Max.Y; y++ {
+				a.image.Set(px, y, a.fg)
+			}
+			continue
+		}
+
+		// draw a guideline on the exact frequency, down to the data
+		for y := area.Min.Y; y < area.Max.Y; y++ {
+			a.image.Set(px, y, a.fg)
 		}
 
 		// draw the text
-		pt := freetype.Pt(px+5, 17)
-		_, _ = a.context.DrawString(str, pt)
+		a.drawText(t.label, image.Pt(px+5, area.Min.Y+int(a.size)+2), area)
 
 	}
 
@@ -124,50 +218,60 @@ func (a *Annotator) DrawXScale() error {
 
 func (a *Annotator) DrawYScale() error {
 
+	if a.layout.TimeAxisArea().Empty() || a.table.TimeStart == nil {
+		return nil
+	}
+
 	log.WithFields(log.Fields{
 		"timestart": a.table.TimeStart.String(),
 		"timeend":   a.table.TimeEnd.String(),
 	}).Debug("annotate Y scale")
 
-	start, end := a.table.TimeStart, a.table.TimeEnd
-
-	// how many samples?
-	count := int(math.Floor(float64(a.table.Integrations) / float64(100)))
-
-	uStart := start.Unix()
-	uEnd := end.Unix()
+	// room for one label every few lines, or every label width when the
+	// labels are side by side
+	count := a.table.Integrations / int(math.Max(1, 4*a.size))
+	if a.layout.Horizontal() {
+		count = a.table.Integrations / (a.measure("0000-00-00 00:00") + int(a.size))
+	}
+	ticks := timeTicks(a.table, count, a.timeLabels)
 
-	secsPerLabel := int(math.Floor(float64(uEnd-uStart) / float64(count)))
-	pxPerLabel := int(math.Floor(float64(a.table.Integrations) / float64(count)))
+	// side by side labels thin out until the widest fits between two ticks
+	for a.layout.Horizontal() && count > 1 && !a.timeLabelsFit(ticks) {
+		count--
+		ticks = timeTicks(a.table, count, a.timeLabels)
+	}
 
 	log.WithFields(log.Fields{
-		"labels":       count,
-		"secsPerLabel": secsPerLabel,
-		"pxPerLabel":   pxPerLabel,
+		"labels": len(ticks),
 	}).Debug("annotate Y scale")
 
-	for si := 0; si < count; si++ {
+	area := a.layout.TimeAxisArea()
 
-		secs := time.Duration(secsPerLabel * si * int(time.Second))
-		px := si * pxPerLabel
+	for _, t := range ticks {
 
-		var str string = ""
+		px := a.layout.TimePos(t.offset)
 
-		if si == 0 {
-			str = start.String()
-		} else {
-			point := start.Add(secs)
-			str = point.Format("15:04:05")
+		if a.layout.Horizontal() {
+			// draw a guideline on the exact time, down to the data
+			for y := area.Min.Y; y < area.Max.Y; y++ {
+				a.image.Set(px, y, a.fg)
+			}
+			// the label runs from its line toward the later times
+			left := px + 5
+			if a.layout.NewestFirst {
+				left = px - 5 - a.measure(t.label)
+			}
+			a.drawText(t.label, image.Pt(left, area.Min.Y+int(a.size)+2), area)
+			continue
 		}
 
-		// draw a guideline on the exact time
-		for i := 0; i < 75; i++ {
-			a.image.Set(i, px, image.White)
+		// draw a guideline on the exact time, up to the data
+		for x := area.Min.X; x < area.Max.X; x++ {
+			a.image.Set(x, px, a.fg)
 		}
 
 		// draw the text, 3 px margin to the line
-		pt := freetype.Pt(3, px-3)
-		_, _ = a.context.DrawString(str, pt)
+		a.drawText(t.label, image.Pt(area.Min.X+3, px-3), area)
 
 	}
 
@@ -175,42 +279,926 @@ func (a *Annotator) DrawYScale() error {
 
 }
 
+// timeLabelsFit tells if the widest of the side by side time labels fits
+// between every two ticks, with its margin to the line and a gap
+func (a *Annotator) timeLabelsFit(ticks []tick) bool {
+	widest := 0
+	for _, t := range ticks {
+		if w := a.measure(t.label); w > widest {
+			widest = w
+		}
+	}
+
+	for i := 1; i < len(ticks); i++ {
+		d := a.layout.TimePos(ticks[i].offset) - a.layout.TimePos(ticks[i-1].offset)
+		if d < 0 {
+			d = -d
+		}
+		if d < widest+5+int(a.size) {
+			return false
+		}
+	}
+	return true
+}
+
+// DrawTitle draws the title and the caption lines centered at the top
+func (a *Annotator) DrawTitle() error {
+
+	area := a.layout.TitleArea()
+	if area.Empty() {
+		return nil
+	}
+
+	y := area.Min.Y + 5
+
+	if a.title != "" {
+		titleSize := a.size * titleScale
+		y += int(titleSize)
+
+		a.context.SetFontSize(titleSize)
+		width := int(float64(a.measure(a.title)) * titleScale)
+		a.drawText(a.title, image.Pt(area.Min.X+(area.Dx()-width)/2, y), area)
+		a.context.SetFontSize(a.size)
+
+		y += int(titleSize*(spacing-1)) + 2
+	}
+
+	for _, caption := range a.captions {
+		y += int(a.size * spacing)
+		width := a.measure(caption)
+		a.drawText(caption, image.Pt(area.Min.X+(area.Dx()-width)/2, y), area)
+	}
+
+	return nil
+}
+
+// DrawInfoBox draws the info template lines outside of the data, or inside
+// one of its corners
 func (a *Annotator) DrawInfoBox() error {
 
-	tStart, tEnd := a.table.TimeStart, a.table.TimeEnd
-	// tDuration := humanize.RelTime(*tStart, *tEnd, "", "")
-	tPixel := (tEnd.Unix() - tStart.Unix()) / int64(a.table.Integrations)
+	lines := expandInfo(InfoLines(a.info), infoFields(a.table, a.meta, a.levels()))
+	if len(lines) == 0 {
+		return nil
+	}
 
-	fStart, fEnd := a.table.HzLow, a.table.HzHigh
-	fBandwidth := fEnd - fStart
-	fPixel := fBandwidth / float64(a.table.Bins)
+	lineHeight := int(a.size * spacing)
+	width := 0
+	for _, line := range lines {
+		if w := a.measure(line); w > width {
+			width = w
+		}
+	}
+	height := len(lines)*lineHeight + 5
 
-	perPixel := fmt.Sprintf("%s x %d seconds", a.humanHz(fPixel), tPixel)
+	data := a.layout.Data
+	area := image.Rect(0, 0, width+6, height).Intersect(image.Rect(0, 0, data.Dx(), data.Dy()))
 
-	// positioning
-	imgSize := a.table.Image().Bounds().Size()
-	top, left := imgSize.Y-75, 3
+	switch a.infoPosition {
+	case InfoTopLeft:
+		area = area.Add(data.Min)
+	case InfoTopRight:
+		area = area.Add(image.Pt(data.Max.X-area.Dx(), data.Min.Y))
+	case InfoBottomLeft:
+		area = area.Add(image.Pt(data.Min.X, data.Max.Y-area.Dy()))
+	case InfoBottomRight:
+		area = area.Add(data.Max.Sub(area.Size()))
+	default:
+		area = a.layout.InfoBoxArea()
+	}
 
-	strings := []string{
-		"Scan start: " + tStart.String(),
-		"Scan end: " + tEnd.String(),
-		// "Scan duration: " + tDuration,
-		fmt.Sprintf("Band: %s to %s", a.humanHz(fStart), a.humanHz(fEnd)),
-		fmt.Sprintf("Bandwidth: %s", a.humanHz(fBandwidth)),
-		"1 pixel = " + perPixel,
+	if area.Empty() {
+		return nil
 	}
 
+	// positioning
+	top, left := area.Min.Y+int(a.size)+3, area.Min.X+3
+
 	// drawing
-	pt := freetype.Pt(left, top)
-	for _, s := range strings {
-		_, _ = a.context.DrawString(s, pt)
-		pt.Y += a.context.PointToFixed(size * spacing)
+	for i, s := range lines {
+		a.drawText(s, image.Pt(left, top+i*lineHeight), area)
+	}
+
+	return nil
+}
+
+// DrawColorBar draws the palette as a gradient from MaxPower at the top to
+// MinPower at the bottom, with dB labels. The bar is drawn in the margin
+// right of the data.
+func (a *Annotator) DrawColorBar() error {
+
+	min, max := *a.table.Config.MinPower, *a.table.Config.MaxPower
+
+	area := a.layout.ColorBarArea()
+	left := area.Min.X + colorBarInset
+	top := area.Min.Y + colorBarInset
+	height := area.Dy() - 2*colorBarInset
+
+	if height < 2 || max <= min {
+		log.Debug("no room for color bar")
+		return nil
+	}
+
+	// power at a pixel row of the bar, linear in dB
+	dbPerPx := (max - min) / float64(height-1)
+
+	for y := 0; y < height; y++ {
+		c := a.palette.Color(a.table, max-float64(y)*dbPerPx)
+		for x := 0; x < colorBarWidth; x++ {
+			a.image.Set(left+x, top+y, c)
+		}
+	}
+
+	// swatches for the over and under colors in the insets
+	if p, ok := a.palette.(Indicators); ok {
+		swatch := func(c color.Color, y int) {
+			if c != nil {
+				rect := image.Rect(left, y, left+colorBarWidth, y+colorBarInset-4)
+				draw.Draw(a.image, rect, image.NewUniform(c), image.ZP, draw.Over)
+			}
+		}
+		over, under, _ := p.IndicatorColors()
+		swatch(over, area.Min.Y+2)
+		swatch(under, top+height+2)
+	}
+
+	step := niceStep(max-min, height/40)
+
+	log.WithFields(log.Fields{
+		"min":  min,
+		"max":  max,
+		"step": step,
+	}).Debug("annotate color bar")
+
+	for _, db := range tickValues(min, max, step) {
+		y := top + int(math.Round((max-db)/dbPerPx))
+
+		for i := 0; i < 5; i++ {
+			a.image.Set(left+colorBarWidth+i, y, a.fg)
+		}
+
+		a.drawText(fmt.Sprintf("%g dB", db), image.Pt(left+colorBarWidth+8, y+5), area)
 	}
 
 	return nil
 }
 
-func (a *Annotator) humanHz(hz float64) string {
+// DrawMarkers draws a labeled line for every marker inside the frequency
+// range of the table, markers with a bandwidth are drawn as shaded bands
+func (a *Annotator) DrawMarkers(markers []*Marker) error {
+
+	data := a.layout.Data
+	drawn := 0
+
+	for _, m := range markers {
+		if m.HzHigh() < a.table.HzLow || m.HzLow() > a.table.HzHigh {
+			continue
+		}
+
+		c := m.color
+		if c == nil {
+			c = a.fg
+		}
+
+		pos := a.layout.FreqPos(a.table.HzBin(m.Hz))
+
+		if m.Bandwidth > 0 {
+			band := a.layout.FreqSpan(a.table.HzBin(m.HzLow()), a.table.HzBin(m.HzHigh()), data)
+
+			draw.Draw(a.image, band, image.NewUniform(withAlpha(c, 0x40)), image.ZP, draw.Over)
+			if a.layout.Horizontal() {
+				a.dashedLine(band.Min.Y, data, c)
+				a.dashedLine(band.Max.Y-1, data, c)
+			} else {
+				a.dashedLine(band.Min.X, data, c)
+				a.dashedLine(band.Max.X-1, data, c)
+			}
+		} else {
+			a.dashedLine(pos, data, c)
+		}
+
+		// the label goes at the start of the data, above a horizontal line
+		label := image.Pt(pos+4, data.Min.Y+int(a.size)+2)
+		if a.layout.Horizontal() {
+			label = image.Pt(data.Min.X+4, pos-4)
+		}
+
+		a.drawTextColor(m.Label, label, data, c)
+		drawn++
+	}
+
+	log.WithFields(log.Fields{
+		"markers": len(markers),
+		"drawn":   drawn,
+	}).Debug("annotate markers")
+
+	return nil
+}
+
+// DrawEvents draws a box around every event inside the table, labeled with
+// its frequency and duration. Boxes are colored by the peak power of the
+// event on the levels of the table it was detected in, or by its class.
+func (a *Annotator) DrawEvents(events []*Event, levels *TableComplex) error {
+
+	data := a.layout.Data
+	drawn := 0
+	labels := []image.Rectangle{}
+
+	for _, e := range events {
+		binLow, binHigh := a.table.HzBin(e.HzLow()), a.table.HzBin(e.HzHigh()-1)
+		rowStart, rowEnd := a.table.TimeRow(e.Start), a.table.TimeRow(e.End)-1
+		if binHigh < 0 || binLow >= a.table.Bins || rowEnd < 0 || rowStart >= a.table.Integrations {
+			continue
+		}
+		if rowEnd < rowStart {
+			rowEnd = rowStart
+		}
+
+		c := a.fg
+		switch a.eventColors {
+		case EventColorClass:
+			if cc, ok := a.classColors[e.Class]; ok {
+				c = cc
+			}
+		default:
+			c = a.palette.Color(levels, e.PeakPower)
+		}
+
+		// the box goes around the cells, not over them
+		box := a.layout.Cells(binLow, binHigh, rowStart, rowEnd).Inset(-1)
+		for x := box.Min.X; x < box.Max.X; x++ {
+			a.setInside(x, box.Min.Y, data, c)
+			a.setInside(x, box.Max.Y-1, data, c)
+		}
+		for y := box.Min.Y; y < box.Max.Y; y++ {
+			a.setInside(box.Min.X, y, data, c)
+			a.setInside(box.Max.X-1, y, data, c)
+		}
+
+		// label above the box, or inside it at the top of the data, unless
+		// it would overlap an earlier label
+		label := fmt.Sprintf("%s %s", humanHz(e.Hz), e.Duration())
+		y := box.Min.Y - 3
+		if y-int(a.size) < data.Min.Y {
+			y = box.Min.Y + int(a.size) + 2
+		}
+
+		rect := image.Rect(box.Min.X, y-int(a.size), box.Min.X+a.measure(label), y+int(a.size/4))
+		overlaps := false
+		for _, r := range labels {
+			overlaps = overlaps || r.Overlaps(rect)
+		}
+		if !overlaps {
+			a.drawTextColor(label, image.Pt(box.Min.X, y), data, c)
+			labels = append(labels, rect)
+		}
+
+		drawn++
+	}
+
+	log.WithFields(log.Fields{
+		"events": len(events),
+		"drawn":  drawn,
+	}).Debug("annotate events")
+
+	return nil
+}
+
+// setInside sets a pixel if it is inside area
+func (a *Annotator) setInside(x, y int, area image.Rectangle, c color.Color) {
+	if image.Pt(x, y).In(area) {
+		a.image.Set(x, y, c)
+	}
+}
+
+// chart padding inside its area
+const chartPad int = 5
+
+// chartValues are the pixels between the chart base and its far edge,
+// values grow up from the bottom or right from the left edge when
+// horizontal
+type chartValues struct {
+	area   image.Rectangle
+	base   int
+	length int
+}
+
+// chart returns the value axis of a chart in area
+func (a *Annotator) chart(area image.Rectangle) chartValues {
+	if a.layout.Horizontal() {
+		return chartValues{area: area, base: area.Min.X + chartPad, length: area.Dx() - 2*chartPad}
+	}
+	return chartValues{area: area, base: area.Max.Y - chartPad, length: area.Dy() - 2*chartPad}
+}
+
+// at returns the canvas point of a value, f is 0 at the base and 1 at the
+// far edge, of the bin at pos on the frequency axis
+func (c chartValues) at(h bool, pos int, f float64) image.Point {
+	offset := int(math.Round(f * float64(c.length)))
+	if h {
+		return image.Pt(c.base+offset, pos)
+	}
+	return image.Pt(pos, c.base-offset)
+}
+
+// pixels is the rectangle covering the pixels from a to b, both included
+func pixels(a, b image.Point) image.Rectangle {
+	r := image.Rectangle{Min: a, Max: b}.Canon()
+	r.Max = r.Max.Add(image.Pt(1, 1))
+	return r
+}
+
+// drawChartGrid draws a dim grid line across the chart at value f with its
+// label next to the chart, left of it or above it when horizontal
+func (a *Annotator) drawChartGrid(c chartValues, f float64, label string) {
+	grid := withAlpha(a.fg, 0x60)
+	area := c.area
+
+	if a.layout.Horizontal() {
+		x := c.at(true, 0, f).X
+		for y := area.Min.Y; y < area.Max.Y; y++ {
+			a.image.Set(x, y, grid)
+		}
+		labels := a.chartLabelArea(c)
+		a.drawText(label, image.Pt(a.chartLabelLeft(c, f, label), area.Min.Y-3), labels)
+		return
+	}
+
+	y := c.at(false, 0, f).Y
+	for x := area.Min.X; x < area.Max.X; x++ {
+		a.image.Set(x, y, grid)
+	}
+	labels := image.Rect(0, area.Min.Y, area.Min.X, area.Max.Y)
+	baseline := y + int(a.size/2) - 1
+	if baseline > labels.Max.Y-2 {
+		baseline = labels.Max.Y - 2
+	}
+	if baseline < labels.Min.Y+int(a.size) {
+		baseline = labels.Min.Y + int(a.size)
+	}
+	a.drawText(label, image.Pt(area.Min.X-a.measure(label)-5, baseline), labels)
+}
+
+// chartLabelArea is the room of the grid labels above a horizontal chart,
+// clear of the charts next to it
+func (a *Annotator) chartLabelArea(c chartValues) image.Rectangle {
+	return image.Rect(c.area.Min.X+chartPad, a.layout.Margins.Title, c.area.Max.X-chartPad, c.area.Min.Y)
+}
+
+// chartLabelLeft is the left of the grid label of value f above a
+// horizontal chart, centered on its grid line and kept in the label area
+func (a *Annotator) chartLabelLeft(c chartValues, f float64, label string) int {
+	labels := a.chartLabelArea(c)
+	left := c.at(true, 0, f).X - a.measure(label)/2
+	if left > labels.Max.X-a.measure(label) {
+		left = labels.Max.X - a.measure(label)
+	}
+	if left < labels.Min.X {
+		left = labels.Min.X
+	}
+	return left
+}
+
+// chartRange rounds the value range of a chart out to whole grid steps,
+// with the finest step whose labels fit next to each other once rounded,
+// or the first step that leaves only the labels at both ends
+func (a *Annotator) chartRange(c chartValues, low, high float64, label func(float64) string) (float64, float64, float64) {
+	step := niceStep(high-low, c.length/int(2*a.size))
+	for {
+		l, h := math.Floor(low/step)*step, math.Ceil(high/step)*step
+		if len(tickValues(l, h, step)) <= 2 || a.chartLabelsFit(c, l, h, step, label) {
+			return l, h, step
+		}
+		step = niceStep(step*1.001, 1)
+	}
+}
+
+// chartLabelsFit tells if the grid labels of a chart from low to high in
+// steps leave a gap of half the font size between them
+func (a *Annotator) chartLabelsFit(c chartValues, low, high, step float64, label func(float64) string) bool {
+	gap := int(a.size / 2)
+	prev, first := 0, true
+	for _, v := range tickValues(low, high, step) {
+		f := (v - low) / (high - low)
+
+		// the labels follow the values right or up
+		if a.layout.Horizontal() {
+			left := a.chartLabelLeft(c, f, label(v))
+			if !first && left < prev+gap {
+				return false
+			}
+			prev = left + a.measure(label(v))
+		} else {
+			y := c.at(false, 0, f).Y
+			if !first && y > prev-int(a.size)-gap {
+				return false
+			}
+			prev = y
+		}
+		first = false
+	}
+	return true
+}
+
+// DrawOccupancy draws the occupancy of every bin as a bar along the
+// frequency axis, colored by the palette from the min to the max power
+func (a *Annotator) DrawOccupancy(o *Occupancy) error {
+
+	area := a.layout.OccupancyArea()
+	if area.Empty() || o == nil {
+		return nil
+	}
+
+	min, max := *a.table.Config.MinPower, *a.table.Config.MaxPower
+	chart := a.chart(area)
+	h := a.layout.Horizontal()
+
+	for _, f := range []float64{0, 0.5, 1} {
+		a.drawChartGrid(chart, f, fmt.Sprintf("%g%%", f*100))
+	}
+
+	for x, f := range o.Fraction {
+		if x >= a.table.Bins || math.IsNaN(f) {
+			continue
+		}
+
+		c := a.palette.Color(a.table, min+f*(max-min))
+		pos := a.layout.FreqPos(x)
+		from, to := chart.at(h, pos, 0), chart.at(h, pos, f)
+		if from == to {
+			continue
+		}
+
+		draw.Draw(a.image, pixels(from, to), image.NewUniform(c), image.ZP, draw.Src)
+	}
+
+	log.WithFields(log.Fields{
+		"bins": len(o.Fraction),
+	}).Debug("annotate occupancy")
+
+	return nil
+}
+
+// trace colors of the spectrum chart
+var spectrumTraces = []struct {
+	name  string
+	color color.Color
+}{
+	{"min", color.RGBA{R: 0x40, G: 0x80, B: 0xff, A: 0xff}},
+	{"median", color.RGBA{G: 0xc0, B: 0xc0, A: 0xff}},
+	{"mean", color.RGBA{R: 0xff, G: 0xd0, A: 0xff}},
+	{"max", color.RGBA{R: 0xff, G: 0x40, B: 0x40, A: 0xff}},
+}
+
+// DrawSpectrum draws the max, mean, median and min traces of the spectrum
+// along the frequency axis, on a dB grid
+func (a *Annotator) DrawSpectrum(s *Spectrum) error {
+
+	area := a.layout.SpectrumArea()
+	if area.Empty() || s == nil {
+		return nil
+	}
+
+	chart := a.chart(area)
+	h := a.layout.Horizontal()
+
+	// keep the lowest dB label clear of the first time label below it
+	if a.layout.SpectrumAbove && !h {
+		chart.base -= int(a.size)
+		chart.length -= int(a.size)
+	}
+
+	// the dB range of the traces, rounded out to whole grid steps
+	low, high := math.Inf(1), math.Inf(-1)
+	for x := range s.Hz {
+		if isFinite(s.Min[x]) {
+			low = math.Min(low, s.Min[x])
+		}
+		if isFinite(s.Max[x]) {
+			high = math.Max(high, s.Max[x])
+		}
+	}
+	if low >= high {
+		log.Debug("no spectrum to draw")
+		return nil
+	}
+
+	dbLabel := func(db float64) string {
+		return fmt.Sprintf("%g dB", db)
+	}
+	low, high, step := a.chartRange(chart, low, high, dbLabel)
+
+	value := func(db float64) float64 {
+		return (db - low) / (high - low)
+	}
+
+	for _, db := range tickValues(low, high, step) {
+		a.drawChartGrid(chart, value(db), dbLabel(db))
+	}
+
+	for _, trace := range spectrumTraces {
+		values := map[string][]float64{
+			"min":    s.Min,
+			"median": s.Median,
+			"mean":   s.Mean,
+			"max":    s.Max,
+		}[trace.name]
+
+		// connect every bin to the one before it
+		var prev *image.Point
+		for x, db := range values {
+			if x >= a.table.Bins || !isFinite(db) {
+				prev = nil
+				continue
+			}
+
+			p := chart.at(h, a.layout.FreqPos(x), value(db))
+			from := p
+			if prev != nil {
+				from = *prev
+				if h {
+					from.Y = p.Y
+				} else {
+					from.X = p.X
+				}
+			}
+
+			draw.Draw(a.image, pixels(from, p).Intersect(area), image.NewUniform(trace.color), image.ZP, draw.Src)
+			prev = &p
+		}
+	}
+
+	// legend in the top left corner, stacked when horizontal
+	pt := image.Pt(area.Min.X+chartPad, area.Min.Y+int(a.size)+2)
+	for i := len(spectrumTraces) - 1; i >= 0; i-- {
+		trace := spectrumTraces[i]
+		a.drawTextColor(trace.name, pt, area, trace.color)
+		if h {
+			pt.Y += int(a.size * spacing)
+		} else {
+			pt.X += a.measure(trace.name) + int(a.size)
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"low":  low,
+		"high": high,
+		"step": step,
+	}).Debug("annotate spectrum")
+
+	return nil
+}
+
+// colors of the traces of the trace plot, in order
+var traceColors = []color.Color{
+	color.RGBA{R: 0xff, G: 0xd0, A: 0xff},
+	color.RGBA{G: 0xc0, B: 0xc0, A: 0xff},
+	color.RGBA{R: 0xff, G: 0x40, B: 0x40, A: 0xff},
+	color.RGBA{R: 0x40, G: 0x80, B: 0xff, A: 0xff},
+	color.RGBA{R: 0x60, G: 0xe0, B: 0x40, A: 0xff},
+	color.RGBA{R: 0xe0, G: 0x60, B: 0xe0, A: 0xff},
+}
+
+// DrawTraces draws the power of every trace against time over the data
+// area, with a dB grid on the left and a time axis below
+func (a *Annotator) DrawTraces(traces []*Trace) error {
+
+	area := a.layout.Data
+	if area.Empty() || len(traces) == 0 || a.table.TimeStart == nil {
+		return nil
+	}
+
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, tr := range traces {
+		for _, db := range tr.Values {
+			if isFinite(db) {
+				low, high = math.Min(low, db), math.Max(high, db)
+			}
+		}
+	}
+	if low >= high {
+		log.Debug("no traces to draw")
+		return nil
+	}
+
+	chart := a.chart(area)
+	step := niceStep(high-low, chart.length/int(2*a.size))
+	low, high = math.Floor(low/step)*step, math.Ceil(high/step)*step
+
+	value := func(db float64) float64 {
+		return (db - low) / (high - low)
+	}
+
+	for _, db := range tickValues(low, high, step) {
+		a.drawChartGrid(chart, value(db), fmt.Sprintf("%g dB", db))
+	}
+
+	// time runs left to right by the row timestamps
+	start, end := *a.table.TimeStart, *a.table.TimeEnd
+	span := float64(end.UnixNano() - start.UnixNano())
+	timeX := func(nanos int64) int {
+		if span <= 0 {
+			return area.Min.X
+		}
+		return area.Min.X + int(math.Round(float64(nanos-start.UnixNano())/span*float64(area.Dx()-1)))
+	}
+
+	labels := image.Rect(0, area.Max.Y, a.image.Bounds().Max.X, a.image.Bounds().Max.Y)
+	right := labels.Min.X
+	for _, t := range timeTicks(a.table, area.Dx()/(a.measure("00:00:00")+int(a.size)), a.timeLabels) {
+		x := timeX(int64(t.value) * int64(time.Second))
+		for y := area.Max.Y; y < area.Max.Y+5; y++ {
+			a.image.Set(x, y, a.fg)
+		}
+
+		left := x - a.measure(t.label)/2
+		if left < labels.Min.X {
+			left = labels.Min.X
+		}
+		if left > labels.Max.X-a.measure(t.label) {
+			left = labels.Max.X - a.measure(t.label)
+		}
+		if left < right {
+			continue
+		}
+		a.drawText(t.label, image.Pt(left, area.Max.Y+5+int(a.size)), labels)
+		right = left + a.measure(t.label) + int(a.size)
+	}
+
+	for i, tr := range traces {
+		c := traceColors[i%len(traceColors)]
+
+		var prev *image.Point
+		for y, db := range tr.Values {
+			if !isFinite(db) || a.table.Rows[y].Time == nil {
+				prev = nil
+				continue
+			}
+
+			p := chart.at(false, timeX(a.table.Rows[y].Time.UnixNano()), value(db))
+			if prev != nil {
+				a.line(*prev, p, area, c)
+			} else {
+				a.setInside(p.X, p.Y, area, c)
+			}
+			prev = &p
+		}
+	}
+
+	// legend above the plot
+	pt := image.Pt(area.Min.X, area.Min.Y-4)
+	legend := image.Rect(area.Min.X, 0, area.Max.X, area.Min.Y)
+	for i, tr := range traces {
+		a.drawTextColor(tr.Label, pt, legend, traceColors[i%len(traceColors)])
+		pt.X += a.measure(tr.Label) + int(a.size)
+	}
+
+	log.WithFields(log.Fields{
+		"traces": len(traces),
+		"low":    low,
+		"high":   high,
+	}).Debug("annotate traces")
+
+	return nil
+}
+
+// line draws a straight line between two points clipped to area
+func (a *Annotator) line(from, to image.Point, area image.Rectangle, c color.Color) {
+	d := to.Sub(from)
+	steps := int(math.Max(math.Abs(float64(d.X)), math.Abs(float64(d.Y))))
+	if steps == 0 {
+		a.setInside(from.X, from.Y, area, c)
+		return
+	}
+
+	for i := 0; i <= steps; i++ {
+		f := float64(i) / float64(steps)
+		x := from.X + int(math.Round(f*float64(d.X)))
+		y := from.Y + int(math.Round(f*float64(d.Y)))
+		a.setInside(x, y, area, c)
+	}
+}
+
+// DrawChannelAxis draws a secondary frequency axis with the channel numbers
+// of a channel plan. Every channel gets a tick, labels are skipped where
+// they would overlap.
+func (a *Annotator) DrawChannelAxis(plan *ChannelPlan) error {
+
+	area := a.layout.ChannelAxisArea()
+	if area.Empty() || plan == nil {
+		return nil
+	}
+
+	gap := int(a.size)
+	labelEnd := area.Min.X - gap
+	if a.layout.Horizontal() {
+		// labels are stacked upwards from the bottom
+		gap = int(a.size / 2)
+		labelEnd = area.Max.Y + gap
+	}
+	drawn := 0
+
+	for _, ch := range plan.Channels {
+		bin := a.table.HzBin(ch.Hz)
+		if bin < 0 || bin >= a.table.Bins {
+			continue
+		}
+
+		pos := a.layout.FreqPos(bin)
+
+		label := ch.Label
+		if drawn == 0 {
+			label = "ch " + label
+		}
+
+		if a.layout.Horizontal() {
+			// tick next to the data
+			for x := area.Max.X - area.Dx()/4; x < area.Max.X; x++ {
+				a.image.Set(x, pos, a.fg)
+			}
+
+			bottom := pos + int(a.size)/2
+			if bottom > labelEnd-gap {
+				continue
+			}
+
+			a.drawText(label, image.Pt(area.Min.X+3, bottom-1), area)
+			labelEnd = bottom - int(a.size)
+			drawn++
+			continue
+		}
+
+		// tick next to the data
+		for y := area.Max.Y - area.Dy()/4; y < area.Max.Y; y++ {
+			a.image.Set(pos, y, a.fg)
+		}
+
+		width := a.measure(label)
+		left := pos - width/2
+		if left < labelEnd+gap {
+			continue
+		}
+
+		a.drawText(label, image.Pt(left, area.Min.Y+int(a.size)+2), area)
+		labelEnd = left + width
+		drawn++
+	}
+
+	log.WithFields(log.Fields{
+		"plan":   plan.Name,
+		"labels": drawn,
+	}).Debug("annotate channel axis")
+
+	return nil
+}
+
+// DrawBandPlans draws each band plan as a strip of colored and labeled
+// bands between the frequency axis and the data
+func (a *Annotator) DrawBandPlans(plans []*BandPlan) error {
+
+	area := a.layout.BandPlanArea()
+	if area.Empty() || len(plans) == 0 {
+		return nil
+	}
+
+	rowHeight := area.Dy() / len(plans)
+	if a.layout.Horizontal() {
+		rowHeight = area.Dx() / len(plans)
+	}
+
+	for i, plan := range plans {
+		row := image.Rect(area.Min.X, area.Min.Y+i*rowHeight, area.Max.X, area.Min.Y+(i+1)*rowHeight-1)
+		if a.layout.Horizontal() {
+			row = image.Rect(area.Min.X+i*rowHeight, area.Min.Y, area.Min.X+(i+1)*rowHeight-1, area.Max.Y)
+		}
+		drawn := 0
+
+		for _, b := range plan.Bands {
+			if b.HzHigh < a.table.HzLow || b.HzLow > a.table.HzHigh {
+				continue
+			}
+
+			c := b.color
+			if c == nil {
+				c = a.fg
+			}
+
+			rect := a.layout.FreqSpan(a.table.HzBin(b.HzLow), a.table.HzBin(b.HzHigh), row)
+			if rect.Empty() {
+				continue
+			}
+
+			draw.Draw(a.image, rect, image.NewUniform(c), image.ZP, draw.Src)
+
+			// center the label if there is room for it
+			width := a.measure(b.Label)
+			if width < rect.Dx()-4 && int(a.size) < rect.Dy()-2 {
+				x := rect.Min.X + (rect.Dx()-width)/2
+				y := rect.Max.Y - (rect.Dy()-int(a.size))/2 - 2
+				a.drawTextStyled(b.Label, image.Pt(x, y), rect, color.Black, TextPlain)
+			}
+
+			drawn++
+		}
+
+		log.WithFields(log.Fields{
+			"plan":  plan.Name,
+			"drawn": drawn,
+		}).Debug("annotate band plan")
+	}
+
+	return nil
+}
+
+// dashedLine draws a dashed line through area at a position along the
+// frequency axis, vertical at x or horizontal at y when horizontal
+func (a *Annotator) dashedLine(pos int, area image.Rectangle, c color.Color) {
+	if a.layout.Horizontal() {
+		if pos < area.Min.Y || pos >= area.Max.Y {
+			return
+		}
+		for x := area.Min.X; x < area.Max.X; x++ {
+			if (x-area.Min.X)%8 < 5 {
+				a.image.Set(x, pos, c)
+			}
+		}
+		return
+	}
+
+	if pos < area.Min.X || pos >= area.Max.X {
+		return
+	}
+
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		if (y-area.Min.Y)%8 < 5 {
+			a.image.Set(pos, y, c)
+		}
+	}
+}
+
+// drawText draws a string with its baseline starting at pt, anything
+// outside of area is clipped
+func (a *Annotator) drawText(str string, pt image.Point, area image.Rectangle) {
+	a.drawTextColor(str, pt, area, a.fg)
+}
+
+// drawTextColor is drawText in another color than the text color
+func (a *Annotator) drawTextColor(str string, pt image.Point, area image.Rectangle, c color.Color) {
+	a.drawTextStyled(str, pt, area, c, a.textStyle)
+}
+
+// drawTextStyled draws text with an outline or a box in the halo color so
+// that it stays readable on top of the data
+func (a *Annotator) drawTextStyled(str string, pt image.Point, area image.Rectangle, c color.Color, style string) {
+	a.context.SetClip(area)
+
+	switch style {
+	case TextBox:
+		ascent, descent := int(math.Ceil(a.size*0.85)), int(math.Ceil(a.size*0.25))
+		box := image.Rect(pt.X-2, pt.Y-ascent-1, pt.X+a.measure(str)+2, pt.Y+descent+1).Intersect(area)
+		draw.Draw(a.image, box, image.NewUniform(withAlpha(a.halo, 0xa0)), image.ZP, draw.Over)
+
+	case TextOutline:
+		a.context.SetSrc(image.NewUniform(a.halo))
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				if dx != 0 || dy != 0 {
+					_, _ = a.context.DrawString(str, freetype.Pt(pt.X+dx, pt.Y+dy))
+				}
+			}
+		}
+	}
+
+	a.context.SetSrc(image.NewUniform(c))
+	_, _ = a.context.DrawString(str, freetype.Pt(pt.X, pt.Y))
+}
+
+// levels describes the power range used for coloring and how it was picked
+func (a *Annotator) levels() string {
+	conf := a.table.Config
+
+	source := func(auto bool, percentile float64) string {
+		if auto {
+			return fmt.Sprintf("p%g", percentile)
+		}
+		return "manual"
+	}
+
+	str := fmt.Sprintf("%0.1f dB (%s) to %0.1f dB (%s)",
+		*conf.MinPower, source(a.table.autoMin, conf.MinPercentile),
+		*conf.MaxPower, source(a.table.autoMax, conf.MaxPercentile))
+
+	if conf.NormalizeRows {
+		str += fmt.Sprintf(" above row floor (p%g)", conf.RowFloorPercentile)
+	}
+
+	if conf.Baseline != "" {
+		str += fmt.Sprintf(" relative to baseline (%s)", path.Base(conf.Baseline))
+	}
+
+	return str
+}
+
+func humanHz(hz float64) string {
 	fpxSI, fpxSuffix := humanize.ComputeSI(hz)
 	return fmt.Sprintf("%0.2f %sHz", fpxSI, fpxSuffix)
 }
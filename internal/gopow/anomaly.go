@@ -0,0 +1,132 @@
+package gopow
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/codegangsta/cli"
+	log "github.com/sirupsen/logrus"
+)
+
+// AnomalyConfig holds the options of the anomaly detection against a
+// baseline. A cell is an anomaly when it is Sigma standard deviations or DB
+// dB over the baseline median of its bin, a zero disables a criterion.
+type AnomalyConfig struct {
+	Baseline string
+	Sigma    float64
+	DB       float64
+	MinCells int
+
+	Events string // events file, stdout when empty
+	Format string // EventsCSV or EventsJSON
+}
+
+// Thresholds returns the anomaly threshold of every bin in the table, +Inf
+// for bins the baseline does not cover
+func (conf *AnomalyConfig) Thresholds(t *TableComplex, b *Baseline) []float64 {
+	threshold := make([]float64, t.Bins)
+	missing := 0
+
+	for x := range threshold {
+		threshold[x] = math.Inf(1)
+
+		i := b.index(t.BinHz(x))
+		if i < 0 || !isFinite(b.Median[i]) {
+			missing++
+			continue
+		}
+
+		if conf.Sigma > 0 && isFinite(b.StdDev[i]) {
+			threshold[x] = b.Median[i] + conf.Sigma*b.StdDev[i]
+		}
+		if conf.DB > 0 {
+			threshold[x] = math.Min(threshold[x], b.Median[i]+conf.DB)
+		}
+	}
+
+	if missing > 0 {
+		log.WithFields(log.Fields{
+			"bins": missing,
+		}).Warn("baseline does not cover all bins, no anomalies there")
+	}
+
+	return threshold
+}
+
+// RunBaseline builds a baseline from the input captures and writes it to
+// the output file, or stdout
+func RunBaseline(c *cli.Context) error {
+	inputs := splitList(c.String("input"))
+	if len(inputs) == 0 {
+		return fmt.Errorf("no input file")
+	}
+
+	conf, err := commandRenderConfig(c)
+	if err != nil {
+		return err
+	}
+
+	tables := []*TableComplex{}
+	for _, input := range inputs {
+		table, err := NewTable(input, conf)
+		if err != nil {
+			return err
+		}
+		tables = append(tables, table)
+	}
+
+	return NewBaseline(tables...).Write(c.String("output"))
+}
+
+// NewAnomaly configures the render of a capture with the cells over the
+// baseline highlighted and boxed as events
+func NewAnomaly(c *cli.Context) (*GoPow, error) {
+	config := commandRunConfig(c, "anomaly")
+	config.Anomaly = &AnomalyConfig{
+		Baseline: c.String("baseline"),
+		Sigma:    c.Float64("sigma"),
+		DB:       c.Float64("db"),
+		MinCells: c.Int("min-cells"),
+		Events:   c.String("events"),
+		Format:   c.String("format"),
+	}
+
+	tuning, err := NewTuningConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	config.Tuning = tuning
+
+	// a dB threshold alone replaces the default sigma
+	if c.IsSet("db") && !c.IsSet("sigma") {
+		config.Anomaly.Sigma = 0
+	}
+
+	if config.InputFile == "" || config.Anomaly.Baseline == "" {
+		return nil, fmt.Errorf("missing input or baseline file")
+	}
+
+	if config.Anomaly.Sigma <= 0 && config.Anomaly.DB <= 0 {
+		return nil, fmt.Errorf("no anomaly threshold, set --sigma or --db")
+	}
+
+	if config.Anomaly.Format == "" {
+		config.Anomaly.Format = eventsFormat(config.Anomaly.Events)
+	}
+
+	switch config.Anomaly.Format {
+	case EventsCSV, EventsJSON:
+	default:
+		return nil, fmt.Errorf("unsupported events format: %s", config.Anomaly.Format)
+	}
+
+	log.WithFields(log.Fields{
+		"input":    config.InputFile,
+		"baseline": config.Anomaly.Baseline,
+		"output":   config.OutputFile,
+	}).Info("GoPow anomaly init")
+
+	return &GoPow{
+		config: config,
+	}, nil
+}
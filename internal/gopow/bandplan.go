@@ -0,0 +1,134 @@
+package gopow
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"image/color"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/dhogborg/rtl-gopow/internal/resources"
+)
+
+const bandPlanDir = "resources/bandplans"
+
+// Band is a frequency range in a band plan
+type Band struct {
+	HzLow  float64
+	HzHigh float64
+	Label  string
+
+	color color.Color
+}
+
+// BandPlan is a named list of bands
+type BandPlan struct {
+	Name  string
+	Bands []*Band
+}
+
+// BandPlanNames lists the embedded band plans
+func BandPlanNames() []string {
+	files, err := resources.AssetDir(bandPlanDir)
+	if err != nil {
+		return []string{}
+	}
+
+	names := []string{}
+	for _, f := range files {
+		names = append(names, strings.TrimSuffix(f, ".csv"))
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// LoadBandPlan reads an embedded band plan by name, or a band plan CSV file
+// with the columns low,high,label,color. Frequencies may have SI suffixes,
+// lines starting with # and a header line are skipped.
+func LoadBandPlan(name string) (*BandPlan, error) {
+	buff, err := resources.Asset(bandPlanDir + "/" + name + ".csv")
+	if err != nil {
+		if _, statErr := os.Stat(name); statErr != nil {
+			return nil, fmt.Errorf("band plan %s is neither embedded nor a file, embedded: %s",
+				name, strings.Join(BandPlanNames(), ","))
+		}
+
+		buff, err = ioutil.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	bands, err := parseBands(buff)
+	if err != nil {
+		return nil, fmt.Errorf("band plan %s: %s", name, err.Error())
+	}
+
+	log.WithFields(log.Fields{
+		"plan":  name,
+		"bands": len(bands),
+	}).Debug("band plan loaded")
+
+	return &BandPlan{
+		Name:  name,
+		Bands: bands,
+	}, nil
+}
+
+func parseBands(buff []byte) ([]*Band, error) {
+	reader := csv.NewReader(bytes.NewReader(buff))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	bands := []*Band{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(record) < 3 {
+			return nil, fmt.Errorf("line %d: expected low,high,label", line)
+		}
+
+		low, err := ParseHz(record[0])
+		if err != nil {
+			if len(bands) == 0 {
+				continue // header
+			}
+			return nil, fmt.Errorf("line %d: %s", line, err.Error())
+		}
+		high, err := ParseHz(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err.Error())
+		}
+
+		b := &Band{
+			HzLow:  low,
+			HzHigh: high,
+			Label:  record[2],
+		}
+
+		if len(record) > 3 && record[3] != "" {
+			b.color, err = ParseColor(record[3])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", line, err.Error())
+			}
+		}
+
+		bands = append(bands, b)
+	}
+
+	return bands, nil
+}
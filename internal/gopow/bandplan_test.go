@@ -0,0 +1,71 @@
+package gopow
+
+import (
+	"testing"
+)
+
+func TestParseBands(t *testing.T) {
+	buff := []byte(`# comment
+low,high,label,color
+88M,108M,FM broadcast,#ff0000
+118M,137M,Airband
+`)
+
+	bands, err := parseBands(buff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bands) != 2 {
+		t.Fatalf("%d bands, want 2", len(bands))
+	}
+
+	fm, air := bands[0], bands[1]
+	if fm.HzLow != 88e6 || fm.HzHigh != 108e6 || fm.Label != "FM broadcast" {
+		t.Errorf("band %g-%g %q", fm.HzLow, fm.HzHigh, fm.Label)
+	}
+	if r, g, b, _ := fm.color.RGBA(); r != 0xffff || g != 0 || b != 0 {
+		t.Errorf("band color %v, want red", fm.color)
+	}
+	if air.Label != "Airband" || air.color != nil {
+		t.Errorf("band %q with color %v, want no color", air.Label, air.color)
+	}
+}
+
+func TestParseBandsErrors(t *testing.T) {
+	tests := []string{
+		"88M,108M\n",
+		"88M,108M,FM\n118M,x,Airband\n",
+		"88M,108M,FM\nx,137M,Airband\n",
+		"88M,108M,FM,notacolor\n",
+	}
+
+	for _, buff := range tests {
+		if _, err := parseBands([]byte(buff)); err == nil {
+			t.Errorf("parseBands(%q) without an error", buff)
+		}
+	}
+}
+
+func TestEmbeddedBandPlans(t *testing.T) {
+	names := BandPlanNames()
+	if len(names) == 0 {
+		t.Fatal("no embedded band plans")
+	}
+
+	for _, name := range names {
+		plan, err := LoadBandPlan(name)
+		if err != nil {
+			t.Errorf("%s: %s", name, err)
+			continue
+		}
+		for _, b := range plan.Bands {
+			if b.HzHigh <= b.HzLow {
+				t.Errorf("%s: band %q %g-%g", name, b.Label, b.HzLow, b.HzHigh)
+			}
+		}
+	}
+
+	if _, err := LoadBandPlan("no-such-plan"); err == nil {
+		t.Error("no error for a missing band plan")
+	}
+}
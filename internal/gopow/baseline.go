@@ -0,0 +1,284 @@
+package gopow
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	BaselineMedian = "median" // compute the baseline from the table itself
+)
+
+// Baseline holds the long term level of every frequency bin, sorted by
+// frequency. Only Median is required, the other statistics are NaN when
+// loaded from a file without them.
+type Baseline struct {
+	Hz     []float64
+	Median []float64
+	P5     []float64
+	P95    []float64
+	StdDev []float64
+}
+
+// NewBaseline computes the statistics of every bin over all rows of the
+// tables, on the frequency grid of the first table
+func NewBaseline(tables ...*TableComplex) *Baseline {
+	log.WithFields(log.Fields{
+		"tables": len(tables),
+	}).Debug("compute baseline")
+
+	t := tables[0]
+	b := &Baseline{
+		Hz:     make([]float64, t.Bins),
+		Median: make([]float64, t.Bins),
+		P5:     make([]float64, t.Bins),
+		P95:    make([]float64, t.Bins),
+		StdDev: make([]float64, t.Bins),
+	}
+
+	column := []float64{}
+	for x := 0; x < t.Bins; x++ {
+		b.Hz[x] = t.BinHz(x)
+
+		column = column[:0]
+		for _, table := range tables {
+			bin := table.HzBin(b.Hz[x])
+			if bin < 0 || bin >= table.Bins {
+				continue
+			}
+			for _, row := range table.Rows {
+				if bin < len(row.Samples) && isFinite(row.Samples[bin]) {
+					column = append(column, row.Samples[bin])
+				}
+			}
+		}
+
+		b.Median[x] = percentile(column, 50)
+		b.P5[x] = percentile(column, 5)
+		b.P95[x] = percentile(column, 95)
+		b.StdDev[x] = stdDev(column)
+	}
+
+	return b
+}
+
+// stdDev is the sample standard deviation of the values, NaN for less than
+// two values
+func stdDev(values []float64) float64 {
+	if len(values) < 2 {
+		return math.NaN()
+	}
+
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	squares := 0.0
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+
+	return math.Sqrt(squares / float64(len(values)-1))
+}
+
+// LoadBaseline reads a baseline CSV file as written by Write
+func LoadBaseline(file string) (*Baseline, error) {
+	log.WithFields(log.Fields{
+		"file": file,
+	}).Debug("loading baseline")
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("baseline %s: %s", file, err.Error())
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[name] = i
+	}
+
+	hzCol, ok := columns["hz"]
+	if !ok {
+		return nil, fmt.Errorf("baseline %s: missing column hz", file)
+	}
+	medianCol, ok := columns["median"]
+	if !ok {
+		return nil, fmt.Errorf("baseline %s: missing column median", file)
+	}
+
+	// optional statistics
+	optional := func(record []string, name string) float64 {
+		col, ok := columns[name]
+		if !ok || col >= len(record) {
+			return math.NaN()
+		}
+		v, err := strconv.ParseFloat(record[col], 64)
+		if err != nil {
+			return math.NaN()
+		}
+		return v
+	}
+
+	b := &Baseline{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("baseline %s: %s", file, err.Error())
+		}
+
+		hz, err := strconv.ParseFloat(record[hzCol], 64)
+		if err != nil {
+			return nil, fmt.Errorf("baseline %s: %s", file, err.Error())
+		}
+		median, err := strconv.ParseFloat(record[medianCol], 64)
+		if err != nil {
+			median = math.NaN()
+		}
+
+		b.Hz = append(b.Hz, hz)
+		b.Median = append(b.Median, median)
+		b.P5 = append(b.P5, optional(record, "p5"))
+		b.P95 = append(b.P95, optional(record, "p95"))
+		b.StdDev = append(b.StdDev, optional(record, "stddev"))
+	}
+
+	if len(b.Hz) == 0 {
+		return nil, fmt.Errorf("baseline %s: no bins found", file)
+	}
+
+	sort.Sort(b)
+
+	return b, nil
+}
+
+// Write the baseline as CSV to file, or stdout when file is empty
+func (b *Baseline) Write(file string) (err error) {
+	w, err := openOutput(file)
+	if err != nil {
+		return err
+	}
+	defer closeOutput(w, &err)
+
+	if file != "" {
+		log.WithFields(log.Fields{
+			"file": file,
+		}).Info("writing baseline")
+	}
+
+	format := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"hz", "median", "p5", "p95", "stddev"})
+
+	for i, hz := range b.Hz {
+		writer.Write([]string{
+			strconv.FormatFloat(hz, 'f', 1, 64),
+			format(b.Median[i]),
+			format(b.P5[i]),
+			format(b.P95[i]),
+			format(b.StdDev[i]),
+		})
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// LevelAt returns the median level of the bin closest to hz, NaN if hz is
+// outside of the baseline
+func (b *Baseline) LevelAt(hz float64) float64 {
+	i := b.index(hz)
+	if i < 0 {
+		return math.NaN()
+	}
+	return b.Median[i]
+}
+
+// index returns the bin closest to hz, -1 if hz is outside of the baseline
+func (b *Baseline) index(hz float64) int {
+	if len(b.Hz) == 0 {
+		return -1
+	}
+
+	// allow half a bin of slack at the edges
+	slack := 0.0
+	if len(b.Hz) > 1 {
+		slack = (b.Hz[len(b.Hz)-1] - b.Hz[0]) / float64(len(b.Hz)-1) / 2
+	}
+	if hz < b.Hz[0]-slack || hz > b.Hz[len(b.Hz)-1]+slack {
+		return -1
+	}
+
+	i := sort.SearchFloat64s(b.Hz, hz)
+	if i == len(b.Hz) || (i > 0 && hz-b.Hz[i-1] < b.Hz[i]-hz) {
+		i--
+	}
+
+	return i
+}
+
+func (b *Baseline) Len() int {
+	return len(b.Hz)
+}
+
+func (b *Baseline) Swap(i, j int) {
+	b.Hz[i], b.Hz[j] = b.Hz[j], b.Hz[i]
+	b.Median[i], b.Median[j] = b.Median[j], b.Median[i]
+	b.P5[i], b.P5[j] = b.P5[j], b.P5[i]
+	b.P95[i], b.P95[j] = b.P95[j], b.P95[i]
+	b.StdDev[i], b.StdDev[j] = b.StdDev[j], b.StdDev[i]
+}
+
+func (b *Baseline) Less(i, j int) bool {
+	return b.Hz[i] < b.Hz[j]
+}
+
+// SubtractBaseline removes the baseline level from every bin in the table,
+// static carriers and spurs are then close to 0 dB
+func (t *TableComplex) SubtractBaseline(b *Baseline) {
+	levels := make([]float64, t.Bins)
+	missing := 0
+	for x := range levels {
+		levels[x] = b.LevelAt(t.BinHz(x))
+		if !isFinite(levels[x]) {
+			missing++
+		}
+	}
+
+	if missing > 0 {
+		log.WithFields(log.Fields{
+			"bins": missing,
+		}).Warn("baseline does not cover all bins, left as is")
+	}
+
+	for _, row := range t.Rows {
+		for x := range row.Samples {
+			if x < len(levels) && isFinite(levels[x]) {
+				row.Samples[x] -= levels[x]
+			}
+		}
+	}
+}
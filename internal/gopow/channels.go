@@ -0,0 +1,146 @@
+package gopow
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/dhogborg/rtl-gopow/internal/resources"
+)
+
+const channelPlanDir = "resources/channels"
+
+// Channel is the center frequency of a numbered channel
+type Channel struct {
+	Hz    float64
+	Label string
+}
+
+// ChannelPlan maps frequencies to channel numbers, sorted by frequency
+type ChannelPlan struct {
+	Name     string
+	Channels []*Channel
+}
+
+// ChannelPlanNames lists the embedded channel plans
+func ChannelPlanNames() []string {
+	files, err := resources.AssetDir(channelPlanDir)
+	if err != nil {
+		return []string{}
+	}
+
+	names := []string{}
+	for _, f := range files {
+		names = append(names, strings.TrimSuffix(f, ".csv"))
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// LoadChannelPlan reads an embedded channel plan by name, or a channel plan
+// CSV file with the columns start,spacing,first,count. Every line numbers
+// count channels from first, spacing Hz apart from the start frequency. A
+// first that is not a number names a single channel, such as DAB block 5A.
+func LoadChannelPlan(name string) (*ChannelPlan, error) {
+	buff, err := resources.Asset(channelPlanDir + "/" + name + ".csv")
+	if err != nil {
+		if _, statErr := os.Stat(name); statErr != nil {
+			return nil, fmt.Errorf("channel plan %s is neither embedded nor a file, embedded: %s",
+				name, strings.Join(ChannelPlanNames(), ","))
+		}
+
+		buff, err = ioutil.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	channels, err := parseChannels(buff)
+	if err != nil {
+		return nil, fmt.Errorf("channel plan %s: %s", name, err.Error())
+	}
+
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].Hz < channels[j].Hz
+	})
+
+	log.WithFields(log.Fields{
+		"plan":     name,
+		"channels": len(channels),
+	}).Debug("channel plan loaded")
+
+	return &ChannelPlan{
+		Name:     name,
+		Channels: channels,
+	}, nil
+}
+
+func parseChannels(buff []byte) ([]*Channel, error) {
+	reader := csv.NewReader(bytes.NewReader(buff))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	channels := []*Channel{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(record) < 4 {
+			return nil, fmt.Errorf("line %d: expected start,spacing,first,count", line)
+		}
+
+		start, err := ParseHz(record[0])
+		if err != nil {
+			if len(channels) == 0 {
+				continue // header
+			}
+			return nil, fmt.Errorf("line %d: %s", line, err.Error())
+		}
+
+		count, err := strconv.Atoi(record[3])
+		if err != nil || count < 1 {
+			return nil, fmt.Errorf("line %d: invalid count %s", line, record[3])
+		}
+
+		first, err := strconv.Atoi(record[2])
+		if err != nil {
+			if count != 1 {
+				return nil, fmt.Errorf("line %d: named channel %s must have count 1", line, record[2])
+			}
+			channels = append(channels, &Channel{Hz: start, Label: record[2]})
+			continue
+		}
+
+		spacing := 0.0
+		if record[1] != "" {
+			spacing, err = ParseHz(record[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", line, err.Error())
+			}
+		}
+
+		for n := 0; n < count; n++ {
+			channels = append(channels, &Channel{
+				Hz:    start + float64(n)*spacing,
+				Label: strconv.Itoa(first + n),
+			})
+		}
+	}
+
+	return channels, nil
+}
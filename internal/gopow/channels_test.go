@@ -0,0 +1,70 @@
+package gopow
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseChannels(t *testing.T) {
+	buff := []byte(`# comment
+start,spacing,first,count
+446.00625M,12.5k,1,3
+174.928M,,5A,1
+`)
+
+	channels, err := parseChannels(buff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Channel{
+		{446.00625e6, "1"},
+		{446.01875e6, "2"},
+		{446.03125e6, "3"},
+		{174.928e6, "5A"},
+	}
+	if len(channels) != len(want) {
+		t.Fatalf("%d channels, want %d", len(channels), len(want))
+	}
+	for i, c := range channels {
+		if math.Abs(c.Hz-want[i].Hz) > 1e-3 || c.Label != want[i].Label {
+			t.Errorf("channel %d: %q at %g, want %q at %g", i, c.Label, c.Hz, want[i].Label, want[i].Hz)
+		}
+	}
+}
+
+func TestParseChannelsErrors(t *testing.T) {
+	tests := []string{
+		"446M,12.5k,1\n",
+		"446M,12.5k,1,0\n",
+		"446M,12.5k,1,x\n",
+		"174.928M,,5A,2\n",
+		"446M,x,1,2\n",
+		"446M,12.5k,1,2\nx,12.5k,3,2\n",
+	}
+
+	for _, buff := range tests {
+		if _, err := parseChannels([]byte(buff)); err == nil {
+			t.Errorf("parseChannels(%q) without an error", buff)
+		}
+	}
+}
+
+func TestLoadChannelPlan(t *testing.T) {
+	plan, err := LoadChannelPlan("pmr446")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Channels) != 16 {
+		t.Fatalf("%d channels, want 16", len(plan.Channels))
+	}
+	for i := 1; i < len(plan.Channels); i++ {
+		if plan.Channels[i].Hz <= plan.Channels[i-1].Hz {
+			t.Fatalf("channels not sorted by frequency at %d", i)
+		}
+	}
+
+	if _, err := LoadChannelPlan("no-such-plan"); err == nil {
+		t.Error("no error for a missing channel plan")
+	}
+}
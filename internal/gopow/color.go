@@ -0,0 +1,66 @@
+package gopow
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+var namedColors = map[string]color.Color{
+	"white":  color.White,
+	"black":  color.Black,
+	"red":    color.RGBA{R: 0xff, A: 0xff},
+	"green":  color.RGBA{G: 0xff, A: 0xff},
+	"blue":   color.RGBA{B: 0xff, A: 0xff},
+	"yellow": color.RGBA{R: 0xff, G: 0xff, A: 0xff},
+	"cyan":   color.RGBA{G: 0xff, B: 0xff, A: 0xff},
+	"pink":   color.RGBA{R: 0xff, B: 0xff, A: 0xff},
+	"gray":   color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff},
+}
+
+// ParseColor reads a color name or a hex color as #rgb, #rrggbb or
+// #rrggbbaa
+func ParseColor(str string) (color.Color, error) {
+	str = strings.ToLower(strings.TrimSpace(str))
+
+	if c, ok := namedColors[str]; ok {
+		return c, nil
+	}
+
+	hex := strings.TrimPrefix(str, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+
+	if len(hex) != 8 {
+		return nil, fmt.Errorf("invalid color: %s", str)
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color: %s", str)
+	}
+
+	// colors are alpha premultiplied
+	c := color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}
+	return color.RGBAModel.Convert(c), nil
+}
+
+// withAlpha returns c with the given opacity
+func withAlpha(c color.Color, alpha uint8) color.Color {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	n.A = alpha
+	return n
+}
+
+// toColorful converts any color, ignoring its alpha
+func toColorful(c color.Color) colorful.Color {
+	r, g, b, _ := c.RGBA()
+	return colorful.Color{R: float64(r) / 0xffff, G: float64(g) / 0xffff, B: float64(b) / 0xffff}
+}
@@ -0,0 +1,297 @@
+package gopow
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/codegangsta/cli"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	ThresholdFloor    = "floor"    // dB above the local noise floor
+	ThresholdAbsolute = "absolute" // absolute power in dB
+)
+
+// share of the scan an event must cover to be continuous
+const continuousCover = 0.9
+
+// event classes
+const (
+	ClassContinuous   = "continuous"   // on for most of the scan
+	ClassIntermittent = "intermittent" // on for several rows
+	ClassBurst        = "burst"        // on for a single row
+)
+
+// DetectConfig holds the detection options
+type DetectConfig struct {
+	Threshold       float64 // dB, absolute or above the floor
+	ThresholdMode   string  // ThresholdFloor or ThresholdAbsolute
+	FloorPercentile float64 // percentile over time taken as the floor of a bin
+	FloorBandwidth  float64 // Hz of neighboring bins the floor is smoothed over
+	MinCells        int     // smaller groups of cells are dropped
+}
+
+// NewDetectConfig reads the detection flags, shared by the detect command
+// and the render
+func NewDetectConfig(c *cli.Context) (*DetectConfig, error) {
+	config := &DetectConfig{
+		Threshold:       c.Float64("threshold"),
+		ThresholdMode:   c.String("threshold-mode"),
+		FloorPercentile: c.Float64("floor-percentile"),
+		MinCells:        c.Int("min-cells"),
+	}
+
+	switch config.ThresholdMode {
+	case ThresholdFloor, ThresholdAbsolute:
+	default:
+		return nil, fmt.Errorf("unsupported threshold mode: %s", config.ThresholdMode)
+	}
+
+	if config.FloorPercentile < 0 || config.FloorPercentile > 100 {
+		return nil, fmt.Errorf("floor percentile out of range: %g", config.FloorPercentile)
+	}
+
+	bw, err := ParseHz(c.String("floor-bandwidth"))
+	if err != nil {
+		return nil, err
+	}
+	config.FloorBandwidth = bw
+
+	return config, nil
+}
+
+// RunDetect loads the input table, detects the events in it and writes them
+// to the output file, or stdout
+func RunDetect(c *cli.Context) error {
+	input, output, format := c.String("input"), c.String("output"), c.String("format")
+	if input == "" {
+		return fmt.Errorf("no input file")
+	}
+
+	if format == "" {
+		format = eventsFormat(output)
+	}
+
+	switch format {
+	case EventsCSV, EventsJSON:
+	default:
+		return fmt.Errorf("unsupported events format: %s", format)
+	}
+
+	config, err := NewDetectConfig(c)
+	if err != nil {
+		return err
+	}
+
+	conf, err := commandRenderConfig(c)
+	if err != nil {
+		return err
+	}
+
+	table, err := NewTable(input, conf)
+	if err != nil {
+		return err
+	}
+
+	events := Detect(table, config)
+
+	return WriteEvents(events, output, format)
+}
+
+// NoiseFloor returns the local noise floor of every bin in the table: the
+// percentile of each bin over time, smoothed by the median of the bins
+// within bandwidth Hz around it so that steady carriers do not raise it
+func NoiseFloor(t *TableComplex, p float64, bandwidth float64) []float64 {
+	columns := make([]float64, t.Bins)
+
+	column := make([]float64, 0, len(t.Rows))
+	for x := 0; x < t.Bins; x++ {
+		column = column[:0]
+		for _, row := range t.Rows {
+			if x < len(row.Samples) {
+				column = append(column, row.Samples[x])
+			}
+		}
+		columns[x] = percentile(column, p)
+	}
+
+	half := int(bandwidth / t.HzPerBin() / 2)
+	if half < 1 {
+		return columns
+	}
+
+	floor := make([]float64, t.Bins)
+	for x := range floor {
+		low, high := x-half, x+half+1
+		if low < 0 {
+			low = 0
+		}
+		if high > t.Bins {
+			high = t.Bins
+		}
+		floor[x] = percentile(columns[low:high], 50)
+	}
+
+	return floor
+}
+
+// Thresholds returns the detection threshold of every bin in the table
+func Thresholds(t *TableComplex, conf *DetectConfig) []float64 {
+	threshold := make([]float64, t.Bins)
+	if conf.ThresholdMode == ThresholdAbsolute {
+		for x := range threshold {
+			threshold[x] = conf.Threshold
+		}
+	} else {
+		for x, floor := range NoiseFloor(t, conf.FloorPercentile, conf.FloorBandwidth) {
+			threshold[x] = floor + conf.Threshold
+		}
+	}
+	return threshold
+}
+
+// Detect marks the cells over the threshold and groups the marked cells
+// that touch, in time, frequency or diagonally, into events sorted by start
+func Detect(t *TableComplex, conf *DetectConfig) []*Event {
+	log.WithFields(log.Fields{
+		"mode":      conf.ThresholdMode,
+		"threshold": conf.Threshold,
+	}).Debug("detect")
+
+	return DetectOver(t, Thresholds(t, conf), conf.MinCells)
+}
+
+// DetectOver groups the cells at or over the threshold of their bin into
+// events, dropping groups of less than minCells cells
+func DetectOver(t *TableComplex, threshold []float64, minCells int) []*Event {
+	over := func(x, y int) bool {
+		row := t.Rows[y]
+		return x < len(row.Samples) && row.Time != nil && row.Samples[x] >= threshold[x]
+	}
+
+	seen := make([]bool, t.Bins*t.Integrations)
+	events := []*Event{}
+	dropped := 0
+
+	for y := 0; y < t.Integrations; y++ {
+		for x := 0; x < t.Bins; x++ {
+			if seen[y*t.Bins+x] || !over(x, y) {
+				continue
+			}
+
+			cells := floodFill(t, x, y, seen, over)
+			if len(cells) < minCells {
+				dropped++
+				continue
+			}
+
+			events = append(events, newEvent(t, cells))
+		}
+	}
+
+	sortEvents(events)
+
+	log.WithFields(log.Fields{
+		"events":  len(events),
+		"dropped": dropped,
+	}).Info("detection")
+
+	return events
+}
+
+// floodFill collects the marked cells connected to x, y
+func floodFill(t *TableComplex, x, y int, seen []bool, over func(x, y int) bool) []cell {
+	cells := []cell{}
+	stack := []cell{{x, y}}
+	seen[y*t.Bins+x] = true
+
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		cells = append(cells, c)
+
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				nx, ny := c.x+dx, c.y+dy
+				if nx < 0 || ny < 0 || nx >= t.Bins || ny >= t.Integrations {
+					continue
+				}
+				if seen[ny*t.Bins+nx] || !over(nx, ny) {
+					continue
+				}
+				seen[ny*t.Bins+nx] = true
+				stack = append(stack, cell{nx, ny})
+			}
+		}
+	}
+
+	return cells
+}
+
+// cell is a cell in the table, bin x of row y
+type cell struct {
+	x, y int
+}
+
+// newEvent summarizes a group of cells
+func newEvent(t *TableComplex, cells []cell) *Event {
+	binLow, binHigh := t.Bins, -1
+	rowStart, rowEnd := t.Integrations, -1
+	peak := math.Inf(-1)
+	linear := 0.0
+
+	for _, c := range cells {
+		if c.x < binLow {
+			binLow = c.x
+		}
+		if c.x > binHigh {
+			binHigh = c.x
+		}
+		if c.y < rowStart {
+			rowStart = c.y
+		}
+		if c.y > rowEnd {
+			rowEnd = c.y
+		}
+
+		p := t.Rows[c.y].Samples[c.x]
+		if p > peak {
+			peak = p
+		}
+		linear += math.Pow(10, p/10)
+	}
+
+	hzPerBin := t.HzPerBin()
+	hzLow := t.HzLow + float64(binLow)*hzPerBin
+	hzHigh := t.HzLow + float64(binHigh+1)*hzPerBin
+
+	// the event lasts until the next row starts, the last row is taken to
+	// last as long as the one before it
+	end := *t.Rows[rowEnd].Time
+	if rowEnd+1 < len(t.Rows) && t.Rows[rowEnd+1].Time != nil {
+		end = *t.Rows[rowEnd+1].Time
+	} else if rowEnd > 0 {
+		end = end.Add(end.Sub(*t.Rows[rowEnd-1].Time))
+	}
+
+	rows := rowEnd - rowStart + 1
+	class := ClassIntermittent
+	switch {
+	case rows == 1:
+		class = ClassBurst
+	case float64(rows) >= continuousCover*float64(t.rowCount()):
+		class = ClassContinuous
+	}
+
+	return &Event{
+		Start:     *t.Rows[rowStart].Time,
+		End:       end,
+		Hz:        (hzLow + hzHigh) / 2,
+		Bandwidth: hzHigh - hzLow,
+		PeakPower: peak,
+		MeanPower: 10 * math.Log10(linear/float64(len(cells))),
+		Cells:     len(cells),
+		Class:     class,
+	}
+}
@@ -0,0 +1,106 @@
+package gopow
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// gridTable is a table of the given samples, rows a minute apart and bins of
+// 100 kHz from 88 MHz
+func gridTable(grid [][]float64) *TableComplex {
+	start := time.Date(2016, 3, 1, 23, 0, 0, 0, time.UTC)
+	table := &TableComplex{
+		Config:       &RenderConfig{},
+		Bins:         len(grid[0]),
+		Integrations: len(grid),
+		HzLow:        88e6,
+		HzHigh:       88e6 + float64(len(grid[0]))*100e3,
+	}
+	for y, samples := range grid {
+		at := start.Add(time.Duration(y) * time.Minute)
+		table.Rows = append(table.Rows, &LineComplex{Time: &at, Samples: samples})
+	}
+	table.TimeStart, table.TimeEnd = table.Rows[0].Time, table.Rows[len(grid)-1].Time
+	return table
+}
+
+func TestDetectOver(t *testing.T) {
+	const o, u = -10.0, -50.0
+	table := gridTable([][]float64{
+		{-5, u, u, u, u, u},
+		{o, u, u, u, o, u},
+		{u, o, u, u, o, u},
+		{u, u, u, u, u, u},
+		{u, u, u, u, u, o},
+	})
+	threshold := []float64{-20, -20, -20, -20, -20, -20}
+	minute := func(m int) time.Time {
+		return table.TimeStart.Add(time.Duration(m) * time.Minute)
+	}
+
+	tests := []struct {
+		minCells int
+		want     []Event
+	}{
+		{2, []Event{
+			{ID: 1, Start: minute(0), End: minute(3), Hz: 88.1e6, Bandwidth: 200e3, PeakPower: -5, Cells: 3, Class: ClassIntermittent},
+			{ID: 2, Start: minute(1), End: minute(3), Hz: 88.45e6, Bandwidth: 100e3, PeakPower: o, Cells: 2, Class: ClassIntermittent},
+		}},
+		{1, []Event{
+			{ID: 1, Start: minute(0), End: minute(3), Hz: 88.1e6, Bandwidth: 200e3, PeakPower: -5, Cells: 3, Class: ClassIntermittent},
+			{ID: 2, Start: minute(1), End: minute(3), Hz: 88.45e6, Bandwidth: 100e3, PeakPower: o, Cells: 2, Class: ClassIntermittent},
+			{ID: 3, Start: minute(4), End: minute(5), Hz: 88.55e6, Bandwidth: 100e3, PeakPower: o, Cells: 1, Class: ClassBurst},
+		}},
+	}
+
+	for _, test := range tests {
+		events := DetectOver(table, threshold, test.minCells)
+		if len(events) != len(test.want) {
+			t.Errorf("min cells %d: %d events, want %d", test.minCells, len(events), len(test.want))
+			continue
+		}
+		for i, e := range events {
+			want := test.want[i]
+			if e.ID != want.ID || !e.Start.Equal(want.Start) || !e.End.Equal(want.End) ||
+				math.Abs(e.Hz-want.Hz) > 1e-3 || math.Abs(e.Bandwidth-want.Bandwidth) > 1e-3 ||
+				e.PeakPower != want.PeakPower || e.Cells != want.Cells || e.Class != want.Class {
+				t.Errorf("min cells %d: event %+v, want %+v", test.minCells, *e, want)
+			}
+		}
+	}
+}
+
+func TestDetectOverContinuous(t *testing.T) {
+	table := gridTable([][]float64{
+		{-30, -30},
+		{-30, -30},
+		{-30, -30},
+	})
+
+	events := DetectOver(table, []float64{-40, -40}, 1)
+	if len(events) != 1 {
+		t.Fatalf("%d events, want 1", len(events))
+	}
+	if e := events[0]; e.Cells != 6 || e.Class != ClassContinuous || math.Abs(e.MeanPower+30) > 1e-9 {
+		t.Errorf("event of %d cells, class %s, mean %g", e.Cells, e.Class, e.MeanPower)
+	}
+}
+
+func TestThresholds(t *testing.T) {
+	table := gridTable([][]float64{
+		{-50, -40},
+		{-48, -40},
+		{-52, -10},
+	})
+
+	absolute := Thresholds(table, &DetectConfig{Threshold: -20, ThresholdMode: ThresholdAbsolute})
+	if absolute[0] != -20 || absolute[1] != -20 {
+		t.Errorf("absolute thresholds %v, want -20", absolute)
+	}
+
+	floor := Thresholds(table, &DetectConfig{Threshold: 10, ThresholdMode: ThresholdFloor, FloorPercentile: 50})
+	if floor[0] != -40 || floor[1] != -30 {
+		t.Errorf("floor thresholds %v, want [-40 -30]", floor)
+	}
+}
@@ -0,0 +1,329 @@
+package gopow
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/codegangsta/cli"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	AlignRelative = "relative" // pair rows by their time since the start of each capture
+	AlignOverlap  = "overlap"  // pair rows by clock time, where the captures overlap
+)
+
+// Diff replaces the table with its difference to other, both resampled to
+// a common frequency grid. Rows are paired with the closest row of other by
+// align, rows without a row of other within one row interval are dropped.
+func (t *TableComplex) Diff(other *TableComplex, align string) error {
+	low, high := math.Max(t.HzLow, other.HzLow), math.Min(t.HzHigh, other.HzHigh)
+	step := math.Max(t.HzPerBin(), other.HzPerBin())
+	bins := int(math.Floor((high-low)/step + 0.5))
+	if bins < 1 {
+		return fmt.Errorf("captures do not overlap in frequency")
+	}
+
+	if t.TimeStart == nil || other.TimeStart == nil {
+		return fmt.Errorf("captures without timestamps")
+	}
+
+	// the slack allowed when pairing rows
+	tolerance := time.Duration(0)
+	if n := other.rowCount(); n > 1 {
+		tolerance = other.TimeEnd.Sub(*other.TimeStart) / time.Duration(n-1)
+	}
+
+	ours, theirs := t.binRanges(low, step, bins), other.binRanges(low, step, bins)
+
+	rows := []*LineComplex{}
+	for _, row := range t.Rows[:t.rowCount()] {
+		at := *row.Time
+		if align == AlignRelative {
+			at = other.TimeStart.Add(row.Time.Sub(*t.TimeStart))
+		}
+
+		y := other.nearestRow(at)
+		if y < 0 || absDuration(other.Rows[y].Time.Sub(at)) > tolerance {
+			continue
+		}
+
+		a, b := resample(row, ours), resample(other.Rows[y], theirs)
+		samples := make([]float64, bins)
+		for x := range samples {
+			samples[x] = math.NaN()
+			if isFinite(a[x]) && isFinite(b[x]) {
+				samples[x] = a[x] - b[x]
+			}
+		}
+
+		rows = append(rows, &LineComplex{
+			Time:    row.Time,
+			Hash:    row.Hash,
+			HzLow:   low,
+			HzHigh:  low + float64(bins)*step,
+			HzStep:  step,
+			Samples: samples,
+		})
+	}
+
+	if len(rows) == 0 {
+		return fmt.Errorf("captures do not overlap in time")
+	}
+
+	log.WithFields(log.Fields{
+		"align": align,
+		"bins":  bins,
+		"rows":  len(rows),
+		"step":  step,
+	}).Info("captures aligned")
+
+	t.Rows = rows
+	t.Bins = bins
+	t.Integrations = len(rows)
+	t.Hops = []int{0}
+	t.HzLow, t.HzHigh = low, low+float64(bins)*step
+	t.TimeStart, t.TimeEnd = rows[0].Time, rows[len(rows)-1].Time
+
+	return nil
+}
+
+// binRanges returns the first and last bin of the table inside every bin of
+// a grid. Grid bins narrower than the table bins get the bin holding their
+// center.
+func (t *TableComplex) binRanges(low, step float64, bins int) [][2]int {
+	ranges := make([][2]int, bins)
+	for x := range ranges {
+		from := low + float64(x)*step
+		first := int(math.Ceil((from-t.HzLow)/t.HzPerBin() - 0.5))
+		last := int(math.Ceil((from+step-t.HzLow)/t.HzPerBin()-0.5)) - 1
+		if last < first {
+			first = t.HzBin(from + step/2)
+			last = first
+		}
+		ranges[x] = [2]int{first, last}
+	}
+	return ranges
+}
+
+// resample averages the power of the bins of a row in every range
+func resample(row *LineComplex, ranges [][2]int) []float64 {
+	samples := make([]float64, len(ranges))
+	for x, r := range ranges {
+		linear, n := 0.0, 0
+		for i := r[0]; i <= r[1]; i++ {
+			if i < 0 || i >= len(row.Samples) || !isFinite(row.Samples[i]) {
+				continue
+			}
+			linear += math.Pow(10, row.Samples[i]/10)
+			n++
+		}
+
+		samples[x] = math.NaN()
+		if n > 0 {
+			samples[x] = 10 * math.Log10(linear/float64(n))
+		}
+	}
+	return samples
+}
+
+// nearestRow returns the row closest to the time, -1 for a table without
+// timed rows
+func (t *TableComplex) nearestRow(at time.Time) int {
+	n := t.rowCount()
+	if n == 0 {
+		return -1
+	}
+
+	y := t.TimeRow(at)
+	if y == n || (y > 0 && at.Sub(*t.Rows[y-1].Time) < t.Rows[y].Time.Sub(at)) {
+		y--
+	}
+	return y
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// DiffStats summarizes a difference table per bin and overall, in dB
+type DiffStats struct {
+	Hz   []float64
+	Mean []float64
+	RMS  []float64
+	Min  []float64
+	Max  []float64
+
+	MeanAll float64
+	RMSAll  float64
+}
+
+// NewDiffStats computes the statistics of every bin of a difference table
+func NewDiffStats(t *TableComplex) *DiffStats {
+	s := &DiffStats{
+		Hz:   make([]float64, t.Bins),
+		Mean: make([]float64, t.Bins),
+		RMS:  make([]float64, t.Bins),
+		Min:  make([]float64, t.Bins),
+		Max:  make([]float64, t.Bins),
+	}
+
+	sumAll, squaresAll, nAll := 0.0, 0.0, 0
+	for x := 0; x < t.Bins; x++ {
+		sum, squares, n := 0.0, 0.0, 0
+		min, max := math.Inf(1), math.Inf(-1)
+		for _, row := range t.Rows {
+			if x >= len(row.Samples) || !isFinite(row.Samples[x]) {
+				continue
+			}
+			v := row.Samples[x]
+			sum += v
+			squares += v * v
+			min, max = math.Min(min, v), math.Max(max, v)
+			n++
+		}
+
+		s.Hz[x] = t.BinHz(x)
+		s.Mean[x] = sum / float64(n)
+		s.RMS[x] = math.Sqrt(squares / float64(n))
+		s.Min[x], s.Max[x] = min, max
+		if n == 0 {
+			s.Min[x], s.Max[x] = math.NaN(), math.NaN()
+		}
+
+		sumAll += sum
+		squaresAll += squares
+		nAll += n
+	}
+
+	s.MeanAll = sumAll / float64(nAll)
+	s.RMSAll = math.Sqrt(squaresAll / float64(nAll))
+
+	return s
+}
+
+// Caption is a one line summary of the differences
+func (s *DiffStats) Caption() string {
+	caption := fmt.Sprintf("Mean %.2f dB, RMS %.2f dB", s.MeanAll, s.RMSAll)
+	if top := s.Top(1); len(top) > 0 {
+		caption += fmt.Sprintf(", largest at %s (RMS %.2f dB)", humanHz(s.Hz[top[0]]), s.RMS[top[0]])
+	}
+	return caption
+}
+
+// Top returns the n bins that differ most by RMS, all bins when n is 0
+func (s *DiffStats) Top(n int) []int {
+	bins := []int{}
+	for x := range s.Hz {
+		if isFinite(s.RMS[x]) {
+			bins = append(bins, x)
+		}
+	}
+
+	sort.SliceStable(bins, func(i, j int) bool {
+		return s.RMS[bins[i]] > s.RMS[bins[j]]
+	})
+
+	if n > 0 && n < len(bins) {
+		bins = bins[:n]
+	}
+	return bins
+}
+
+// Write the n bins that differ most as CSV to file, or stdout when file is
+// empty
+func (s *DiffStats) Write(file string, n int) (err error) {
+	w, err := openOutput(file)
+	if err != nil {
+		return err
+	}
+	defer closeOutput(w, &err)
+
+	if file != "" {
+		log.WithFields(log.Fields{
+			"file": file,
+		}).Info("writing difference statistics")
+	}
+
+	format := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	}
+
+	writer := csv.NewWriter(w)
+	err = writer.Write([]string{"hz", "mean", "rms", "min", "max"})
+	if err != nil {
+		return err
+	}
+
+	for _, x := range s.Top(n) {
+		err = writer.Write([]string{
+			strconv.FormatFloat(s.Hz[x], 'f', 0, 64),
+			format(s.Mean[x]),
+			format(s.RMS[x]),
+			format(s.Min[x]),
+			format(s.Max[x]),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// NewDiff configures the render of the difference between two captures,
+// with the diverging palette and the default annotations
+func NewDiff(c *cli.Context) (*GoPow, error) {
+	config := commandRunConfig(c, "diff")
+	config.Compare = c.String("compare")
+	config.Align = c.String("align")
+	config.DiffStats = c.String("stats")
+	config.DiffTop = c.Int("top")
+	config.Palette = "diverging"
+	config.MinPercentile, config.MaxPercentile = 1, 99
+
+	tuning, err := NewTuningConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	config.Tuning = tuning
+
+	if config.InputFile == "" || config.Compare == "" {
+		return nil, fmt.Errorf("missing input or compare file")
+	}
+
+	switch config.Align {
+	case AlignRelative, AlignOverlap:
+	default:
+		return nil, fmt.Errorf("unsupported alignment: %s", config.Align)
+	}
+
+	if c.IsSet("range") {
+		if c.Float64("range") <= 0 {
+			return nil, fmt.Errorf("invalid range: %g", c.Float64("range"))
+		}
+		config.MaxPower, config.MinPower = c.Float64("range"), -c.Float64("range")
+	}
+
+	if config.Annotation.Title == "" {
+		config.Annotation.Title = fmt.Sprintf("%s - %s", config.InputFile, config.Compare)
+	}
+
+	log.WithFields(log.Fields{
+		"input":   config.InputFile,
+		"compare": config.Compare,
+		"output":  config.OutputFile,
+	}).Info("GoPow diff init")
+
+	return &GoPow{
+		config: config,
+	}, nil
+}
@@ -0,0 +1,261 @@
+package gopow
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	EventsCSV  = "csv"
+	EventsJSON = "json"
+)
+
+// EventsDetect detects the events to overlay during the render instead of
+// loading them from a file
+const EventsDetect = "detect"
+
+const (
+	EventColorPower = "power" // the palette color of the peak power
+	EventColorClass = "class" // a color per event class
+)
+
+// DefaultClassColors are the event colors by class, events of other classes
+// are drawn in the text color
+var DefaultClassColors = map[string]color.Color{
+	ClassContinuous:   namedColors["cyan"],
+	ClassIntermittent: namedColors["yellow"],
+	ClassBurst:        namedColors["pink"],
+}
+
+// Event is a transmission, a group of touching cells over the detection
+// threshold
+type Event struct {
+	ID        int       `json:"id"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Hz        float64   `json:"frequency"`
+	Bandwidth float64   `json:"bandwidth"`
+	PeakPower float64   `json:"peak_power"`
+	MeanPower float64   `json:"mean_power"`
+	Cells     int       `json:"cells"`
+	Class     string    `json:"class,omitempty"`
+}
+
+// HzLow is the lower edge of the event
+func (e *Event) HzLow() float64 {
+	return e.Hz - e.Bandwidth/2
+}
+
+// HzHigh is the upper edge of the event
+func (e *Event) HzHigh() float64 {
+	return e.Hz + e.Bandwidth/2
+}
+
+// Duration of the event
+func (e *Event) Duration() time.Duration {
+	return e.End.Sub(e.Start)
+}
+
+// eventsHeader are the columns of the events CSV
+var eventsHeader = []string{
+	"id", "start", "end", "duration", "frequency", "bandwidth", "peak_power", "mean_power", "cells", "class",
+}
+
+// sortEvents orders the events by start and frequency and numbers them
+func sortEvents(events []*Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Start.Equal(events[j].Start) {
+			return events[i].Start.Before(events[j].Start)
+		}
+		return events[i].Hz < events[j].Hz
+	})
+
+	for i, e := range events {
+		e.ID = i + 1
+	}
+}
+
+// LoadEvents reads an events file as written by WriteEvents, JSON when the
+// file ends in .json and CSV otherwise. CSV columns are found by the header
+// names, start, end and frequency are required.
+func LoadEvents(file string) ([]*Event, error) {
+	log.WithFields(log.Fields{
+		"file": file,
+	}).Debug("loading events")
+
+	var events []*Event
+	var err error
+
+	if eventsFormat(file) == EventsJSON {
+		events, err = readEventsJSON(file)
+	} else {
+		events, err = readEventsCSV(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("events %s: %s", file, err.Error())
+	}
+
+	return events, nil
+}
+
+func readEventsJSON(file string) ([]*Event, error) {
+	buff, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []*Event{}
+	err = json.Unmarshal(buff, &events)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func readEventsCSV(file string) ([]*Event, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[name] = i
+	}
+
+	for _, name := range []string{"start", "end", "frequency"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %s", name)
+		}
+	}
+
+	events := []*Event{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return record[i]
+		}
+
+		number := func(name string) float64 {
+			v, err := strconv.ParseFloat(field(name), 64)
+			if err != nil {
+				return 0
+			}
+			return v
+		}
+
+		e := &Event{
+			Hz:        number("frequency"),
+			Bandwidth: number("bandwidth"),
+			PeakPower: number("peak_power"),
+			MeanPower: number("mean_power"),
+			Cells:     int(number("cells")),
+			Class:     field("class"),
+		}
+		e.ID, _ = strconv.Atoi(field("id"))
+
+		e.Start, err = time.Parse(time.RFC3339, field("start"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err.Error())
+		}
+		e.End, err = time.Parse(time.RFC3339, field("end"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err.Error())
+		}
+
+		events = append(events, e)
+	}
+
+	return events, nil
+}
+
+// eventsFormat picks the format from the file extension, CSV by default
+func eventsFormat(file string) string {
+	if strings.ToLower(filepath.Ext(file)) == ".json" {
+		return EventsJSON
+	}
+	return EventsCSV
+}
+
+// WriteEvents writes the events to file, or stdout when file is empty
+func WriteEvents(events []*Event, file string, format string) (err error) {
+	w, err := openOutput(file)
+	if err != nil {
+		return err
+	}
+	defer closeOutput(w, &err)
+
+	if file != "" {
+		log.WithFields(log.Fields{
+			"file":   file,
+			"events": len(events),
+		}).Info("writing events")
+	}
+
+	if format == EventsJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(events)
+	}
+
+	writer := csv.NewWriter(w)
+	err = writer.Write(eventsHeader)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range events {
+		err = writer.Write([]string{
+			strconv.Itoa(e.ID),
+			e.Start.Format(time.RFC3339),
+			e.End.Format(time.RFC3339),
+			strconv.FormatFloat(e.Duration().Seconds(), 'f', -1, 64),
+			strconv.FormatFloat(e.Hz, 'f', 0, 64),
+			strconv.FormatFloat(e.Bandwidth, 'f', 0, 64),
+			fmt.Sprintf("%.2f", e.PeakPower),
+			fmt.Sprintf("%.2f", e.MeanPower),
+			strconv.Itoa(e.Cells),
+			e.Class,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
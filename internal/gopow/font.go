@@ -0,0 +1,95 @@
+package gopow
+
+import (
+	"fmt"
+	"image/color"
+	"io/ioutil"
+	"math"
+	"os"
+
+	"github.com/golang/freetype"
+	"github.com/golang/freetype/truetype"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/image/font"
+
+	"github.com/dhogborg/rtl-gopow/internal/resources"
+)
+
+const (
+	FontSizeAuto = 0 // scale the font with the image
+
+	// smallest font size that can be set, in points
+	FontSizeSetMin float64 = 4
+
+	// limits of the automatic font size, in points
+	fontSizeMin float64 = 9
+	fontSizeMax float64 = 48
+)
+
+// FontConfig selects the font used for annotations
+type FontConfig struct {
+	Font    string      // name of an embedded font or path to a TTF file
+	Size    float64     // size in points, or FontSizeAuto
+	Hinting string      // none or full
+	Color   color.Color // text and guideline color
+}
+
+// SizeFor returns the configured font size, or a size scaled to the
+// dimensions of the table when the size is automatic. A 1200x300 table
+// gets the classic 15 points.
+func (f FontConfig) SizeFor(table *TableComplex) float64 {
+	return f.SizeForArea(table.Bins, table.Integrations)
+}
+
+// SizeForArea is SizeFor for a data area of the given size, for plots that
+// do not draw the table cell by cell
+func (f FontConfig) SizeForArea(width, height int) float64 {
+	if f.Size != FontSizeAuto {
+		return f.Size
+	}
+
+	size := math.Sqrt(float64(width)*float64(height)) / 40
+	size = math.Max(fontSizeMin, math.Min(fontSizeMax, size))
+
+	return math.Round(size)
+}
+
+// Measurer returns a function that gives the width in pixels of a string
+// drawn in the configured font at the given size
+func (f FontConfig) Measurer(size float64) (func(string) int, error) {
+	ttf, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+
+	face := truetype.NewFace(ttf, &truetype.Options{
+		Size: size,
+		DPI:  dpi,
+	})
+
+	return func(str string) int {
+		return font.MeasureString(face, str).Ceil()
+	}, nil
+}
+
+// load parses the configured font, embedded fonts are looked up by name in
+// resources/fonts before the name is tried as a file path
+func (f FontConfig) load() (*truetype.Font, error) {
+	fontBytes, err := resources.Asset("resources/fonts/" + f.Font + ".ttf")
+	if err != nil {
+		if _, statErr := os.Stat(f.Font); statErr != nil {
+			return nil, fmt.Errorf("font %s is neither embedded nor a file", f.Font)
+		}
+
+		fontBytes, err = ioutil.ReadFile(f.Font)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"font": f.Font,
+	}).Debug("font loaded")
+
+	return freetype.ParseFont(fontBytes)
+}
@@ -3,9 +3,13 @@ package gopow
 import (
 	"fmt"
 	"image"
+	"image/color"
 	"image/jpeg"
 	"image/png"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/codegangsta/cli"
@@ -25,12 +29,63 @@ type RunConfig struct {
 	MaxPower    float64
 	MinPower    float64
 	Palette     string
+
+	Orientation string
+	NewestFirst bool
+
+	// indicator colors, nil uses the palette
+	OverColor   color.Color
+	UnderColor  color.Color
+	NoDataColor color.Color
+
+	MinPercentile float64
+	MaxPercentile float64
+
+	NormalizeRows      bool
+	RowFloorPercentile float64
+
+	HopCrop float64
+	DCBins  int
+	Tuning  *TuningConfig
+
+	EqualizeHops       bool
+	HopFloorPercentile float64
+
+	Baseline     string
+	SaveBaseline string
+
+	Compare   string // second capture of a difference render
+	Align     string // AlignRelative or AlignOverlap
+	DiffStats string // difference statistics CSV, stdout when empty
+	DiffTop   int    // bins in the difference statistics, 0 for all
+
+	Anomaly *AnomalyConfig // highlight the cells over a baseline
+
+	Margins    Margins
+	Annotation AnnotatorConfig
+	Markers    string
+	BandPlans  []string
+	Channels   string
+	Events     string // EventsDetect or an events file
+	Detect     *DetectConfig
+	Occupancy  string // OccupancyBelow or a chart file
+
+	Spectrum     string // SpectrumAbove, SpectrumBelow or a chart file
+	SpectrumCSV  string
+	SpectrumFrom string // window of the spectrum, see ParseWindowTime
+	SpectrumTo   string
 }
 
 type GoPow struct {
-	config    *RunConfig
-	image     *image.RGBA
-	timestamp time.Time
+	config      *RunConfig
+	image       *image.RGBA
+	occupancy   *image.RGBA   // occupancy chart written to its own file
+	spectrum    *image.RGBA   // spectrum chart written to its own file
+	spectrumCSV *Spectrum     // spectrum written as CSV
+	levels      *TableComplex // table of the detect and occupancy commands, see levelsTable
+	diff        *DiffStats    // difference statistics written as CSV
+	anomalies   []*Event      // anomaly events written as CSV or JSON
+	timestamp   time.Time
 }
 
 func NewGoPow(c *cli.Context) (*GoPow, error) {
@@ -42,6 +97,141 @@ func NewGoPow(c *cli.Context) (*GoPow, error) {
 		MaxPower:    c.Float64("max-power"),
 		MinPower:    c.Float64("min-power"),
 		Palette:     c.String("palette"),
+
+		Orientation: c.String("orientation"),
+		NewestFirst: c.Bool("newest-first"),
+
+		MinPercentile: c.Float64("min-percentile"),
+		MaxPercentile: c.Float64("max-percentile"),
+
+		NormalizeRows:      c.Bool("normalize-rows"),
+		RowFloorPercentile: c.Float64("row-floor-percentile"),
+
+		HopCrop: c.Float64("hop-crop"),
+		DCBins:  c.Int("dc-bins"),
+
+		EqualizeHops:       c.Bool("equalize-hops"),
+		HopFloorPercentile: c.Float64("hop-floor-percentile"),
+
+		Baseline:     c.String("baseline"),
+		SaveBaseline: c.String("save-baseline"),
+		Markers:      c.String("markers"),
+		BandPlans:    splitList(c.String("bandplan")),
+		Channels:     c.String("channels"),
+		Events:       c.String("events"),
+		Occupancy:    c.String("occupancy"),
+
+		Spectrum:     c.String("spectrum"),
+		SpectrumCSV:  c.String("spectrum-csv"),
+		SpectrumFrom: c.String("spectrum-from"),
+		SpectrumTo:   c.String("spectrum-to"),
+
+		Margins: Margins{
+			Title:       optionalInt(c, "margin-title", MarginAuto),
+			FreqAxis:    optionalInt(c, "margin-freq-axis", MarginAuto),
+			ChannelAxis: optionalInt(c, "margin-channel-axis", MarginAuto),
+			BandPlan:    optionalInt(c, "margin-band-plan", MarginAuto),
+			TimeAxis:    optionalInt(c, "margin-time-axis", MarginAuto),
+			ColorBar:    optionalInt(c, "margin-color-bar", MarginAuto),
+			InfoBox:     optionalInt(c, "margin-info-box", MarginAuto),
+			Occupancy:   optionalInt(c, "margin-occupancy", MarginAuto),
+			Spectrum:    optionalInt(c, "margin-spectrum", MarginAuto),
+		},
+		Annotation: AnnotatorConfig{
+			Font: FontConfig{
+				Font:    c.String("font"),
+				Size:    c.Float64("font-size"),
+				Hinting: c.String("font-hinting"),
+			},
+			TimeLabels:  c.String("time-labels"),
+			Theme:       c.String("theme"),
+			TextStyle:   c.String("text-style"),
+			EventColors: c.String("event-colors"),
+			ClassColors: map[string]color.Color{},
+
+			Title:        c.String("title"),
+			Captions:     c.StringSlice("caption"),
+			Info:         c.String("info"),
+			InfoPosition: c.String("info-position"),
+			Meta: InfoMeta{
+				Site:    c.String("site"),
+				Antenna: c.String("antenna"),
+				Gain:    c.String("gain"),
+			},
+		},
+	}
+
+	for name, target := range map[string]*color.Color{
+		"over-color":    &config.OverColor,
+		"under-color":   &config.UnderColor,
+		"no-data-color": &config.NoDataColor,
+	} {
+		if c.String(name) == "" {
+			continue
+		}
+		col, err := ParseColor(c.String(name))
+		if err != nil {
+			return nil, err
+		}
+		*target = col
+	}
+
+	for class, col := range DefaultClassColors {
+		config.Annotation.ClassColors[class] = col
+	}
+
+	for _, item := range splitList(c.String("event-class-colors")) {
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid event class color: %s", item)
+		}
+		col, err := ParseColor(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		config.Annotation.ClassColors[strings.TrimSpace(parts[0])] = col
+	}
+
+	for _, str := range []string{config.SpectrumFrom, config.SpectrumTo} {
+		if str != "" {
+			if _, err := ParseWindowTime(str, time.Time{}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// a duration and a time compare only against the scan start, the render
+	// checks those when it has the table
+	if config.SpectrumFrom != "" && config.SpectrumTo != "" {
+		from, _ := ParseWindowTime(config.SpectrumFrom, time.Time{})
+		to, _ := ParseWindowTime(config.SpectrumTo, time.Time{})
+		if isDuration(config.SpectrumFrom) == isDuration(config.SpectrumTo) && from.After(to) {
+			return nil, fmt.Errorf("spectrum window from %s to %s ends before it starts", config.SpectrumFrom, config.SpectrumTo)
+		}
+	}
+
+	detect, err := NewDetectConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	config.Detect = detect
+
+	config.Tuning, err = NewTuningConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.String("text-color") != "" {
+		fg, err := ParseColor(c.String("text-color"))
+		if err != nil {
+			return nil, err
+		}
+		config.Annotation.Font.Color = fg
+	}
+
+	err = config.validateStyle()
+	if err != nil {
+		return nil, err
 	}
 
 	if !c.IsSet("max-power") {
@@ -55,6 +245,39 @@ func NewGoPow(c *cli.Context) (*GoPow, error) {
 		return nil, fmt.Errorf("missing input file")
 	}
 
+	if config.MinPercentile < 0 || config.MaxPercentile > 100 ||
+		config.MinPercentile >= config.MaxPercentile {
+		return nil, fmt.Errorf("invalid percentiles: %g-%g", config.MinPercentile, config.MaxPercentile)
+	}
+
+	if config.HopCrop < 0 || config.HopCrop >= 50 {
+		return nil, fmt.Errorf("hop crop out of range: %g", config.HopCrop)
+	}
+
+	if config.DCBins < 0 {
+		return nil, fmt.Errorf("invalid DC bins: %d", config.DCBins)
+	}
+
+	if config.HopFloorPercentile < 0 || config.HopFloorPercentile > 100 {
+		return nil, fmt.Errorf("hop floor percentile out of range: %g", config.HopFloorPercentile)
+	}
+
+	if config.Occupancy == OccupancyBelow && !config.Annotations {
+		return nil, fmt.Errorf("--occupancy below is drawn with the annotations, use a chart file with --no-annotations")
+	}
+
+	if (config.Spectrum == SpectrumAbove || config.Spectrum == SpectrumBelow) && !config.Annotations {
+		return nil, fmt.Errorf("--spectrum %s is drawn with the annotations, use a chart file with --no-annotations", config.Spectrum)
+	}
+
+	if config.Events != "" && !config.Annotations {
+		return nil, fmt.Errorf("--events is drawn with the annotations, not with --no-annotations")
+	}
+
+	if config.SaveBaseline != "" && config.Baseline == "" {
+		return nil, fmt.Errorf("--save-baseline requires --baseline")
+	}
+
 	if config.Format == "" {
 		config.Format = "png"
 	}
@@ -80,8 +303,289 @@ func NewGoPow(c *cli.Context) (*GoPow, error) {
 	return g, nil
 }
 
+// newSpectrum computes the spectrum over the configured window
+func (g *GoPow) newSpectrum(table *TableComplex) (*Spectrum, error) {
+	if table.TimeStart == nil {
+		return NewSpectrum(table, nil, nil), nil
+	}
+
+	var from, to *time.Time
+	if g.config.SpectrumFrom != "" {
+		t, err := ParseWindowTime(g.config.SpectrumFrom, *table.TimeStart)
+		if err != nil {
+			return nil, err
+		}
+		from = &t
+	}
+	if g.config.SpectrumTo != "" {
+		t, err := ParseWindowTime(g.config.SpectrumTo, *table.TimeStart)
+		if err != nil {
+			return nil, err
+		}
+		to = &t
+	}
+
+	if from != nil && to != nil && from.After(*to) {
+		return nil, fmt.Errorf("spectrum window from %s to %s ends before it starts", g.config.SpectrumFrom, g.config.SpectrumTo)
+	}
+
+	return NewSpectrum(table, from, to), nil
+}
+
+// writeImage encodes the image to file as png or jpeg
+func writeImage(img image.Image, file string, format string) (err error) {
+	out, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer closeOutput(out, &err)
+
+	switch format {
+	case "png":
+		return png.Encode(out, img)
+
+	case "jpeg", "jpg":
+		opt := &jpeg.Options{
+			Quality: 98,
+		}
+		return jpeg.Encode(out, img, opt)
+
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// openOutput creates the file to export to, or returns stdout when file is
+// empty. Close the output with closeOutput.
+func openOutput(file string) (io.WriteCloser, error) {
+	if file == "" {
+		return stdout{}, nil
+	}
+	return os.Create(file)
+}
+
+// closeOutput closes an output and keeps its error in err unless err holds
+// an earlier one, for a deferred close
+func closeOutput(out io.Closer, err *error) {
+	if cerr := out.Close(); *err == nil {
+		*err = cerr
+	}
+}
+
+// stdout is os.Stdout that is left open on close
+type stdout struct{}
+
+func (stdout) Write(p []byte) (int, error) {
+	return os.Stdout.Write(p)
+}
+
+func (stdout) Close() error {
+	return nil
+}
+
+// imageFormat picks the image format from the file extension, png by
+// default
+func imageFormat(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".jpg", ".jpeg":
+		return "jpeg"
+	}
+	return "png"
+}
+
+// commandRunConfig is the render configuration of the commands that render
+// a waterfall without the render flags, with their defaults. The output
+// defaults to <input>.<name>.png.
+func commandRunConfig(c *cli.Context, name string) *RunConfig {
+	config := &RunConfig{
+		InputFile:   c.String("input"),
+		OutputFile:  c.String("output"),
+		Format:      imageFormat(c.String("output")),
+		Annotations: true,
+		MaxPower:    PowerConfigAuto,
+		MinPower:    PowerConfigAuto,
+		Palette:     "spectrum",
+
+		Orientation: OrientationVertical,
+		NoDataColor: color.Gray{Y: 0x80},
+
+		MinPercentile: 5,
+		MaxPercentile: 99.9,
+
+		Margins: Margins{
+			Title:       MarginAuto,
+			FreqAxis:    MarginAuto,
+			ChannelAxis: MarginAuto,
+			BandPlan:    MarginAuto,
+			TimeAxis:    MarginAuto,
+			ColorBar:    MarginAuto,
+			InfoBox:     MarginAuto,
+			Occupancy:   MarginAuto,
+			Spectrum:    MarginAuto,
+		},
+		Annotation: AnnotatorConfig{
+			Font:         FontConfig{Font: "luxisr"},
+			TimeLabels:   TimeLabelsAbsolute,
+			Theme:        ThemeDark,
+			TextStyle:    TextOutline,
+			EventColors:  EventColorPower,
+			ClassColors:  DefaultClassColors,
+			Title:        c.String("title"),
+			Info:         DefaultInfoTemplate,
+			InfoPosition: InfoOutside,
+		},
+	}
+
+	if config.OutputFile == "" {
+		config.OutputFile = config.InputFile + "." + name + ".png"
+	}
+
+	return config
+}
+
+// commandRenderConfig is the table config of the commands that export data
+// rather than an image. The levels are not used there but every table has
+// them.
+func commandRenderConfig(c *cli.Context) (*RenderConfig, error) {
+	tuning, err := NewTuningConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RenderConfig{
+		MinPercentile: 5,
+		MaxPercentile: 99.9,
+		Tuning:        tuning,
+	}, nil
+}
+
+// commandChartConfig is the config of the commands that draw a chart in an
+// image file of their own, with the chart flags over the command defaults
+func commandChartConfig(c *cli.Context, name string) (*RunConfig, error) {
+	config := commandRunConfig(c, name)
+	config.Palette = c.String("palette")
+	config.Annotation.Font.Font = c.String("font")
+	config.Annotation.Font.Size = c.Float64("font-size")
+	config.Annotation.Theme = c.String("theme")
+	config.Annotation.TextStyle = TextPlain
+
+	// not every chart has an orientation
+	if orientation := c.String("orientation"); orientation != "" {
+		config.Orientation = orientation
+	}
+
+	err := config.validateStyle()
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// validateStyle checks the options of the look of the waterfall and its
+// charts, shared by the render and the commands that draw charts
+func (config *RunConfig) validateStyle() error {
+	if config.Annotation.Font.Size != FontSizeAuto && config.Annotation.Font.Size < FontSizeSetMin {
+		return fmt.Errorf("invalid font size: %g, 0 for auto or at least %g", config.Annotation.Font.Size, FontSizeSetMin)
+	}
+
+	err := ValidateInfoTemplate(config.Annotation.Info)
+	if err != nil {
+		return err
+	}
+
+	switch config.Orientation {
+	case OrientationVertical, OrientationHorizontal:
+	default:
+		return fmt.Errorf("unsupported orientation: %s", config.Orientation)
+	}
+
+	switch config.Annotation.InfoPosition {
+	case InfoOutside, InfoTopLeft, InfoTopRight, InfoBottomLeft, InfoBottomRight:
+	default:
+		return fmt.Errorf("unsupported info position: %s", config.Annotation.InfoPosition)
+	}
+
+	switch config.Annotation.Theme {
+	case ThemeAuto, ThemeDark, ThemeLight:
+	default:
+		return fmt.Errorf("unsupported theme: %s", config.Annotation.Theme)
+	}
+
+	switch config.Annotation.TextStyle {
+	case TextPlain, TextOutline, TextBox:
+	default:
+		return fmt.Errorf("unsupported text style: %s", config.Annotation.TextStyle)
+	}
+
+	switch config.Annotation.TimeLabels {
+	case TimeLabelsAbsolute, TimeLabelsRelative:
+	default:
+		return fmt.Errorf("unsupported time labels: %s", config.Annotation.TimeLabels)
+	}
+
+	switch config.Annotation.EventColors {
+	case EventColorPower, EventColorClass:
+	default:
+		return fmt.Errorf("unsupported event colors: %s", config.Annotation.EventColors)
+	}
+
+	return nil
+}
+
+// newPalette returns the palette by name, the spectrum palette for unknown
+// names
+func newPalette(name string) Palette {
+	switch name {
+	case "yellow":
+		return &YellowPalette{}
+	case "diverging":
+		return &DivergingPalette{}
+	default:
+		return &SpectrumPalette{}
+	}
+}
+
+// splitList splits a comma separated flag value
+func splitList(str string) []string {
+	list := []string{}
+	for _, s := range strings.Split(str, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// optionalInt returns the value of a flag, or def if it was not set
+func optionalInt(c *cli.Context, name string, def int) int {
+	if !c.IsSet(name) {
+		return def
+	}
+	return c.Int(name)
+}
+
 func (g *GoPow) Render() error {
-	conf := &RenderConfig{}
+	conf := &RenderConfig{
+		MinPercentile: g.config.MinPercentile,
+		MaxPercentile: g.config.MaxPercentile,
+
+		NormalizeRows:      g.config.NormalizeRows,
+		RowFloorPercentile: g.config.RowFloorPercentile,
+
+		HopCrop: g.config.HopCrop,
+		DCBins:  g.config.DCBins,
+		Tuning:  g.config.Tuning,
+
+		EqualizeHops:       g.config.EqualizeHops,
+		HopFloorPercentile: g.config.HopFloorPercentile,
+
+		Baseline:     g.config.Baseline,
+		SaveBaseline: g.config.SaveBaseline,
+
+		Compare: g.config.Compare,
+		Align:   g.config.Align,
+	}
 
 	if g.config.MaxPower != PowerConfigAuto {
 		conf.MaxPower = &g.config.MaxPower
@@ -91,12 +595,11 @@ func (g *GoPow) Render() error {
 		conf.MinPower = &g.config.MinPower
 	}
 
-	var palette Palette
-	switch g.config.Palette {
-	case "yellow":
-		palette = &YellowPalette{}
-	default:
-		palette = &SpectrumPalette{}
+	var palette Palette = &IndicatorPalette{
+		Palette: newPalette(g.config.Palette),
+		Over:    g.config.OverColor,
+		Under:   g.config.UnderColor,
+		NoData:  g.config.NoDataColor,
 	}
 
 	log.Debug("staring render")
@@ -107,16 +610,115 @@ func (g *GoPow) Render() error {
 		return err
 	}
 
-	g.image = table.Image()
+	if g.config.Compare != "" {
+		g.diff = NewDiffStats(table)
+		g.config.Annotation.Captions = append(g.config.Annotation.Captions, g.diff.Caption())
+	}
+
+	if g.config.Anomaly != nil {
+		baseline, err := LoadBaseline(g.config.Anomaly.Baseline)
+		if err != nil {
+			return err
+		}
+
+		threshold := g.config.Anomaly.Thresholds(table, baseline)
+		palette = &HighlightPalette{
+			Palette:   palette,
+			Threshold: threshold,
+		}
+		g.anomalies = DetectOver(table, threshold, g.config.Anomaly.MinCells)
+	}
+
+	plans := []*BandPlan{}
+	for _, name := range g.config.BandPlans {
+		plan, err := LoadBandPlan(name)
+		if err != nil {
+			return err
+		}
+		plans = append(plans, plan)
+	}
+
+	var channels *ChannelPlan
+	if g.config.Channels != "" {
+		channels, err = LoadChannelPlan(g.config.Channels)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the charts in their own files get the margins of an annotated
+	// waterfall even without annotations, for their axes
+	margins, chartMargins := Margins{}, Margins{}
+	if g.config.Annotations || g.config.Occupancy != "" || g.config.Spectrum != "" {
+		size := g.config.Annotation.Font.SizeFor(table)
+		measure, err := g.config.Annotation.Font.Measurer(size)
+		if err != nil {
+			return err
+		}
+
+		defaults := DefaultMargins(size, measure, g.config.Orientation)
+		defaults.BandPlan *= len(plans)
+		defaults.Title = TitleMargin(size, g.config.Annotation.Title, g.config.Annotation.Captions)
+		defaults.InfoBox = textMargin(size, float64(len(InfoLines(g.config.Annotation.Info))))
+		if g.config.Annotation.InfoPosition != InfoOutside {
+			defaults.InfoBox = 0
+		}
+		if channels == nil {
+			defaults.ChannelAxis = 0
+		}
+		if g.config.Occupancy == "" {
+			defaults.Occupancy = 0
+		}
+		if g.config.Spectrum == "" {
+			defaults.Spectrum = 0
+		}
+
+		chartMargins = g.config.Margins.Resolve(defaults)
+	}
+	if g.config.Annotations {
+		margins = chartMargins
+	}
+
+	// separate charts have their margin, the waterfall does not
+	if g.config.Occupancy != OccupancyBelow {
+		margins.Occupancy = 0
+	}
+	if g.config.Spectrum != SpectrumAbove && g.config.Spectrum != SpectrumBelow {
+		margins.Spectrum = 0
+	}
+
+	var spectrum *Spectrum
+	if g.config.Spectrum != "" || g.config.SpectrumCSV != "" {
+		spectrum, err = g.newSpectrum(table)
+		if err != nil {
+			return err
+		}
+	}
+	if g.config.SpectrumCSV != "" {
+		g.spectrumCSV = spectrum
+	}
+
+	layout := NewLayout(table, margins, LayoutConfig{
+		Orientation:   g.config.Orientation,
+		NewestFirst:   g.config.NewestFirst,
+		SpectrumAbove: g.config.Spectrum == SpectrumAbove,
+	})
+	theme, err := ResolveTheme(g.config.Annotation.Theme, palette, table)
+	if err != nil {
+		return err
+	}
+
+	g.image = layout.Image(theme.Background)
 
 	for y, row := range table.Rows {
 		for x := range row.Samples {
-			g.image.Set(x, y, palette.ColorAt(table, x, y))
+			p := layout.DataPoint(x, y)
+			g.image.Set(p.X, p.Y, palette.ColorAt(table, x, y))
 		}
 	}
 
 	if g.config.Annotations {
-		annotator, err := NewAnnotator(g.image, table)
+		annotator, err := NewAnnotator(g.image, table, palette, layout, g.config.Annotation)
 		if err != nil {
 			return err
 		}
@@ -124,40 +726,155 @@ func (g *GoPow) Render() error {
 		// add some frequency and time annotation
 		annotator.DrawXScale()
 		annotator.DrawYScale()
+		annotator.DrawTitle()
 		annotator.DrawInfoBox()
+		annotator.DrawColorBar()
+		annotator.DrawChannelAxis(channels)
+		annotator.DrawBandPlans(plans)
+
+		if g.config.Markers != "" {
+			markers, err := LoadMarkers(g.config.Markers)
+			if err != nil {
+				return err
+			}
+			annotator.DrawMarkers(markers)
+		}
+
+		if g.config.Events != "" {
+			// events are detected and colored on the levels the detect
+			// command sees, an events file is from that command
+			levels, err := g.levelsTable(table, conf)
+			if err != nil {
+				return err
+			}
+
+			var events []*Event
+			if g.config.Events == EventsDetect {
+				events = Detect(levels, g.config.Detect)
+			} else {
+				events, err = LoadEvents(g.config.Events)
+				if err != nil {
+					return err
+				}
+			}
+			annotator.DrawEvents(events, levels)
+		}
+
+		if g.anomalies != nil {
+			annotator.DrawEvents(g.anomalies, table)
+		}
+
+		if g.config.Spectrum == SpectrumAbove || g.config.Spectrum == SpectrumBelow {
+			annotator.DrawSpectrum(spectrum)
+		}
+
+		if g.config.Occupancy == OccupancyBelow {
+			levels, err := g.levelsTable(table, conf)
+			if err != nil {
+				return err
+			}
+			annotator.DrawOccupancy(NewOccupancy(levels, g.config.Detect))
+		}
+	}
+
+	if g.config.Spectrum != "" && g.config.Spectrum != SpectrumAbove && g.config.Spectrum != SpectrumBelow {
+		g.spectrum, err = SpectrumChart(table, spectrum, palette, chartMargins, g.config.Orientation, g.config.Annotation)
+		if err != nil {
+			return err
+		}
+	}
+
+	if g.config.Occupancy != "" && g.config.Occupancy != OccupancyBelow {
+		// on the levels of the occupancy command, for the same threshold
+		levels, err := g.levelsTable(table, conf)
+		if err != nil {
+			return err
+		}
+
+		occupancy := NewOccupancy(levels, g.config.Detect)
+		g.occupancy, err = OccupancyChart(table, occupancy, palette, chartMargins, g.config.Orientation, g.config.Annotation)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// levelsTable is the table as the detect and occupancy commands load it,
+// without the row normalization, baseline subtraction and hop processing of
+// the render. It is the render table when the render does none of them.
+func (g *GoPow) levelsTable(table *TableComplex, conf *RenderConfig) (*TableComplex, error) {
+	if !conf.NormalizeRows && conf.Baseline == "" && !conf.EqualizeHops && conf.HopCrop == 0 && conf.DCBins == 0 {
+		return table, nil
+	}
+
+	if g.levels == nil {
+		levels, err := NewTable(g.config.InputFile, &RenderConfig{
+			MinPercentile: conf.MinPercentile,
+			MaxPercentile: conf.MaxPercentile,
+			Tuning:        conf.Tuning,
+		})
+		if err != nil {
+			return nil, err
+		}
+		g.levels = levels
+	}
+
+	return g.levels, nil
+}
+
 func (g *GoPow) Write() error {
 	log.WithFields(log.Fields{
 		"file": g.config.OutputFile,
 	}).Debug("staring output write")
 
-	out, err := os.Create(g.config.OutputFile)
+	err := writeImage(g.image, g.config.OutputFile, g.config.Format)
 	if err != nil {
 		return err
 	}
 
-	switch g.config.Format {
-	case "png":
-		err = png.Encode(out, g.image)
-		break
+	if g.spectrum != nil {
+		log.WithFields(log.Fields{
+			"file": g.config.Spectrum,
+		}).Debug("writing spectrum chart")
 
-	case "jpeg", "jpg":
-		opt := &jpeg.Options{
-			Quality: 98,
+		err = writeImage(g.spectrum, g.config.Spectrum, imageFormat(g.config.Spectrum))
+		if err != nil {
+			return err
 		}
-		err = jpeg.Encode(out, g.image, opt)
-		break
+	}
 
-	default:
-		return fmt.Errorf("unsupported format: %s", g.config.Format)
+	if g.spectrumCSV != nil {
+		err = g.spectrumCSV.Write(g.config.SpectrumCSV)
+		if err != nil {
+			return err
+		}
 	}
 
-	if err != nil {
-		return err
+	if g.diff != nil {
+		err = g.diff.Write(g.config.DiffStats, g.config.DiffTop)
+		if err != nil {
+			return err
+		}
+	}
+
+	if g.config.Anomaly != nil {
+		err = WriteEvents(g.anomalies, g.config.Anomaly.Events, g.config.Anomaly.Format)
+		if err != nil {
+			return err
+		}
+	}
+
+	if g.occupancy != nil {
+		log.WithFields(log.Fields{
+			"file": g.config.Occupancy,
+		}).Debug("writing occupancy chart")
+
+		err = writeImage(g.occupancy, g.config.Occupancy, imageFormat(g.config.Occupancy))
+		if err != nil {
+			return err
+		}
 	}
 
 	duration := humanize.RelTime(g.timestamp, time.Now(), "", "")
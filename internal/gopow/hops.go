@@ -0,0 +1,169 @@
+package gopow
+
+import (
+	"math"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// InterpolateDC replaces the n bins at the center of a single hop, where the
+// rtl-sdr shows its DC spike, with a line between the bins around them
+func (l *LineComplex) InterpolateDC(n int) {
+	from, to := dcBins(len(l.Samples), n)
+	if from < to {
+		interpolate(l.Samples, from, to)
+	}
+}
+
+// dcBins returns the first bin InterpolateDC replaces in a hop of the given
+// number of bins and the first bin after them, both 0 when it replaces none
+func dcBins(bins, n int) (int, int) {
+	if n <= 0 || bins <= n+1 {
+		return 0, 0
+	}
+	from := bins/2 - n/2
+	return from, from + n
+}
+
+// CropHopEdges replaces the given percentage of bins at both edges of every
+// hop with a line between the nearest bins kept in the hops next to them, to
+// hide the roll off of the tuner filter. The outer edges of the band hold
+// the nearest kept bin.
+func (l *LineComplex) CropHopEdges(percent float64) {
+	if percent <= 0 {
+		return
+	}
+
+	cropped := make([]bool, len(l.Samples))
+	for i, start := range l.Hops {
+		end := len(l.Samples)
+		if i+1 < len(l.Hops) {
+			end = l.Hops[i+1]
+		}
+
+		k := cropBins(end-start, percent)
+		for x := 0; x < k; x++ {
+			cropped[start+x] = true
+			cropped[end-1-x] = true
+		}
+	}
+
+	// fill every run of cropped bins
+	for x := 0; x < len(cropped); x++ {
+		if !cropped[x] {
+			continue
+		}
+		end := x
+		for end < len(cropped) && cropped[end] {
+			end++
+		}
+		interpolate(l.Samples, x, end)
+		x = end
+	}
+}
+
+// cropBins returns the number of bins CropHopEdges replaces at each edge of
+// a hop of the given number of bins
+func cropBins(bins int, percent float64) int {
+	if percent <= 0 {
+		return 0
+	}
+	k := int(math.Round(float64(bins) * percent / 100))
+	if 2*k >= bins {
+		k = (bins - 1) / 2
+	}
+	return k
+}
+
+// interpolate replaces the samples from up to to with a line between the
+// samples on both sides of them, holding one side when the other is missing
+// or not finite, and NaN when both are
+func interpolate(samples []float64, from, to int) {
+	left, right := math.NaN(), math.NaN()
+	if from > 0 {
+		left = samples[from-1]
+	}
+	if to < len(samples) {
+		right = samples[to]
+	}
+
+	switch {
+	case !isFinite(left) && !isFinite(right):
+		left, right = math.NaN(), math.NaN()
+	case !isFinite(left):
+		left = right
+	case !isFinite(right):
+		right = left
+	}
+
+	span := float64(to - from + 1)
+	for x := from; x < to; x++ {
+		f := float64(x-from+1) / span
+		samples[x] = left + f*(right-left)
+	}
+}
+
+// EqualizeHops estimates the noise floor of every hop as the percentile p
+// of its samples over the whole scan and offsets each hop so that its floor
+// matches the median floor of all hops, removing the seams between hops
+// with different gains. The bins filled in by the hop crop and the DC
+// removal are left out of the floor.
+func (t *TableComplex) EqualizeHops(p float64) {
+	if len(t.Hops) < 2 {
+		return
+	}
+
+	floors := make([]float64, len(t.Hops))
+	for i := range t.Hops {
+		start, end := t.hopBins(i)
+		crop := cropBins(end-start, t.Config.HopCrop)
+
+		// the DC bins are mirrored when the tuning inverted the hop
+		dcFrom, dcTo := dcBins(end-start, t.Config.DCBins)
+		mirrorFrom, mirrorTo := end-start-dcTo, end-start-dcFrom
+		interpolated := func(x int) bool {
+			x -= start
+			return x < crop || x >= end-start-crop ||
+				(x >= dcFrom && x < dcTo) || (x >= mirrorFrom && x < mirrorTo)
+		}
+
+		samples := []float64{}
+		for _, row := range t.Rows {
+			for x := start; x < end && x < len(row.Samples); x++ {
+				if !interpolated(x) {
+					samples = append(samples, row.Samples[x])
+				}
+			}
+		}
+		floors[i] = percentile(samples, p)
+	}
+
+	reference := percentile(floors, 50)
+	for i := range t.Hops {
+		if !isFinite(floors[i]) {
+			continue
+		}
+
+		offset := reference - floors[i]
+		start, end := t.hopBins(i)
+		for _, row := range t.Rows {
+			for x := start; x < end && x < len(row.Samples); x++ {
+				row.Samples[x] += offset
+			}
+		}
+
+		log.WithFields(log.Fields{
+			"hop":    i,
+			"floor":  floors[i],
+			"offset": offset,
+		}).Debug("equalize hop")
+	}
+}
+
+// hopBins returns the first bin of hop i and the first bin after it
+func (t *TableComplex) hopBins(i int) (int, int) {
+	if i+1 < len(t.Hops) {
+		return t.Hops[i], t.Hops[i+1]
+	}
+	return t.Hops[i], t.Bins
+}
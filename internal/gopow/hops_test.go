@@ -0,0 +1,167 @@
+package gopow
+
+import (
+	"math"
+	"testing"
+)
+
+// samplesEqual compares samples, NaN equal to NaN
+func samplesEqual(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.IsNaN(a[i]) != math.IsNaN(b[i]) || (!math.IsNaN(a[i]) && math.Abs(a[i]-b[i]) > 1e-9) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCropBins(t *testing.T) {
+	tests := []struct {
+		bins    int
+		percent float64
+		want    int
+	}{
+		{100, 5, 5},
+		{10, 0, 0},
+		{10, -5, 0},
+		{10, 12.5, 1},
+		{4, 50, 1},
+		{2, 50, 0},
+	}
+
+	for _, test := range tests {
+		if got := cropBins(test.bins, test.percent); got != test.want {
+			t.Errorf("cropBins(%d, %g) = %d, want %d", test.bins, test.percent, got, test.want)
+		}
+	}
+}
+
+func TestDCBins(t *testing.T) {
+	tests := []struct {
+		bins, n  int
+		from, to int
+	}{
+		{10, 2, 4, 6},
+		{10, 3, 4, 7},
+		{10, 0, 0, 0},
+		{3, 2, 0, 0},
+	}
+
+	for _, test := range tests {
+		if from, to := dcBins(test.bins, test.n); from != test.from || to != test.to {
+			t.Errorf("dcBins(%d, %d) = %d, %d, want %d, %d", test.bins, test.n, from, to, test.from, test.to)
+		}
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		samples  []float64
+		from, to int
+		want     []float64
+	}{
+		{[]float64{0, 9, 9, 9, 4}, 1, 4, []float64{0, 1, 2, 3, 4}},
+		{[]float64{9, 9, 5}, 0, 2, []float64{5, 5, 5}},
+		{[]float64{5, 9, 9}, 1, 3, []float64{5, 5, 5}},
+		{[]float64{nan, 9, -3}, 1, 2, []float64{nan, -3, -3}},
+		{[]float64{nan, 9, nan}, 1, 2, []float64{nan, nan, nan}},
+	}
+
+	for _, test := range tests {
+		samples := append([]float64(nil), test.samples...)
+		interpolate(samples, test.from, test.to)
+		if !samplesEqual(samples, test.want) {
+			t.Errorf("interpolate(%v, %d, %d) = %v, want %v", test.samples, test.from, test.to, samples, test.want)
+		}
+	}
+}
+
+func TestCropHopEdges(t *testing.T) {
+	line := &LineComplex{
+		Hops:    []int{0, 5},
+		Samples: []float64{-50, -40, -40, -40, -60, -70, -30, -30, -30, -50},
+	}
+	line.CropHopEdges(20)
+
+	want := []float64{-40, -40, -40, -40, -40 + 10.0/3, -40 + 20.0/3, -30, -30, -30, -30}
+	if !samplesEqual(line.Samples, want) {
+		t.Errorf("samples %v, want %v", line.Samples, want)
+	}
+}
+
+func TestInterpolateDC(t *testing.T) {
+	line := &LineComplex{Samples: []float64{-40, -40, -40, -40, -10, -10, -30, -30, -30, -30}}
+	line.InterpolateDC(2)
+
+	want := []float64{-40, -40, -40, -40, -40 + 10.0/3, -40 + 20.0/3, -30, -30, -30, -30}
+	if !samplesEqual(line.Samples, want) {
+		t.Errorf("samples %v, want %v", line.Samples, want)
+	}
+}
+
+func TestEqualizeHops(t *testing.T) {
+	tests := []struct {
+		name string
+		conf RenderConfig
+		grid [][]float64
+		p    float64
+		want [][]float64
+	}{
+		{
+			name: "floors",
+			p:    50,
+			grid: [][]float64{
+				{-40, -40, -40, -40, -35, -35, -35, -35, -30, -30, -30, -30},
+				{-40, -10, -40, -40, -35, -35, -35, -35, -30, -30, -30, -30},
+			},
+			want: [][]float64{
+				{-35, -35, -35, -35, -35, -35, -35, -35, -35, -35, -35, -35},
+				{-35, -5, -35, -35, -35, -35, -35, -35, -35, -35, -35, -35},
+			},
+		},
+		{
+			name: "crop left out",
+			conf: RenderConfig{HopCrop: 25},
+			grid: [][]float64{
+				{-40, -40, -40, -40, -35, -35, -35, -35, -100, -30, -30, -100},
+			},
+			want: [][]float64{
+				{-35, -35, -35, -35, -35, -35, -35, -35, -105, -35, -35, -105},
+			},
+		},
+		{
+			name: "DC left out",
+			conf: RenderConfig{DCBins: 1},
+			grid: [][]float64{
+				{-40, -40, -40, -40, -35, -100, -100, -35, -30, -30, -30, -30},
+			},
+			want: [][]float64{
+				{-35, -35, -35, -35, -35, -100, -100, -35, -35, -35, -35, -35},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		table := gridTable(test.grid)
+		table.Config = &test.conf
+		table.Hops = []int{0, 4, 8}
+		table.EqualizeHops(test.p)
+
+		for y, row := range table.Rows {
+			if !samplesEqual(row.Samples, test.want[y]) {
+				t.Errorf("%s: row %d = %v, want %v", test.name, y, row.Samples, test.want[y])
+			}
+		}
+	}
+
+	single := gridTable([][]float64{{-40, -30}})
+	single.Hops = []int{0}
+	single.EqualizeHops(10)
+	if !samplesEqual(single.Rows[0].Samples, []float64{-40, -30}) {
+		t.Errorf("single hop changed: %v", single.Rows[0].Samples)
+	}
+}
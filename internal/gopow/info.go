@@ -0,0 +1,126 @@
+package gopow
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	InfoOutside     = "outside" // in the margin below the data
+	InfoTopLeft     = "top-left"
+	InfoTopRight    = "top-right"
+	InfoBottomLeft  = "bottom-left"
+	InfoBottomRight = "bottom-right"
+)
+
+// DefaultInfoTemplate reproduces the classic info box, lines are separated
+// by | and {field} is replaced by the value of the field
+const DefaultInfoTemplate = "Scan start: {start}|Scan end: {end}|Band: {band}|Bandwidth: {bandwidth}|1 pixel = {pixel}|Power: {power}"
+
+var infoFieldPattern = regexp.MustCompile(`\{([a-z]+)\}`)
+
+// InfoMeta is user supplied information about the capture
+type InfoMeta struct {
+	Site    string
+	Antenna string
+	Gain    string
+}
+
+// infoFields returns the values of every field available to info templates
+func infoFields(table *TableComplex, meta InfoMeta, power string) map[string]string {
+	fields := map[string]string{
+		"file":      path.Base(table.File),
+		"site":      meta.Site,
+		"antenna":   meta.Antenna,
+		"gain":      meta.Gain,
+		"power":     power,
+		"band":      fmt.Sprintf("%s to %s", humanHz(table.HzLow), humanHz(table.HzHigh)),
+		"bandwidth": humanHz(table.HzHigh - table.HzLow),
+		"bins":      fmt.Sprintf("%d", table.Bins),
+		"rows":      fmt.Sprintf("%d", table.Integrations),
+		"samples":   "",
+		"start":     "",
+		"end":       "",
+		"duration":  "",
+		"pixel":     humanHz(table.HzPerBin()),
+	}
+
+	if len(table.Rows) > 0 {
+		fields["samples"] = fmt.Sprintf("%d", table.Rows[0].SampleCount)
+	}
+
+	if table.TimeStart != nil && table.TimeEnd != nil {
+		tStart, tEnd := table.TimeStart, table.TimeEnd
+		tPixel := (tEnd.Unix() - tStart.Unix()) / int64(table.Integrations)
+
+		fields["start"] = tStart.String()
+		fields["end"] = tEnd.String()
+		fields["duration"] = tEnd.Sub(*tStart).Round(time.Second).String()
+		fields["pixel"] = fmt.Sprintf("%s x %d seconds", humanHz(table.HzPerBin()), tPixel)
+	}
+
+	return fields
+}
+
+// InfoFieldNames lists the fields available to info templates
+func InfoFieldNames() []string {
+	names := []string{}
+	for name := range infoFields(&TableComplex{}, InfoMeta{}, "") {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateInfoTemplate checks that a template only uses known fields
+func ValidateInfoTemplate(tmpl string) error {
+	known := map[string]bool{}
+	for _, name := range InfoFieldNames() {
+		known[name] = true
+	}
+
+	for _, match := range infoFieldPattern.FindAllStringSubmatch(tmpl, -1) {
+		if !known[match[1]] {
+			return fmt.Errorf("unknown info field {%s}, available: %s", match[1], strings.Join(InfoFieldNames(), ","))
+		}
+	}
+
+	return nil
+}
+
+// InfoLines splits a template in lines, lines whose fields are all empty
+// are dropped
+func InfoLines(tmpl string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(tmpl, "|") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// expandInfo fills in the fields of the template lines
+func expandInfo(lines []string, fields map[string]string) []string {
+	expanded := []string{}
+	for _, line := range lines {
+		empty := true
+		str := infoFieldPattern.ReplaceAllStringFunc(line, func(match string) string {
+			value := fields[match[1:len(match)-1]]
+			if value != "" {
+				empty = false
+			}
+			return value
+		})
+
+		if empty && infoFieldPattern.MatchString(line) {
+			continue
+		}
+		expanded = append(expanded, str)
+	}
+	return expanded
+}
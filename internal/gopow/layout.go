@@ -0,0 +1,357 @@
+package gopow
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// MarginAuto picks a margin from the font size
+const MarginAuto = -1
+
+const (
+	OrientationVertical   = "vertical"   // frequency across, time flowing down
+	OrientationHorizontal = "horizontal" // time across, frequency going up
+)
+
+// Margins are the sizes in pixels of the annotation areas around the data.
+// In the vertical orientation the title, frequency axis, channel axis and
+// band plan strip are stacked above the data and the time axis is left of
+// it. In the horizontal orientation the frequency axis, channel axis and band
+// plan strip are stacked left of the data and the time axis is above it,
+// below the title. The color bar is always right of the data and the info
+// box below. The spectrum and occupancy charts follow the frequency axis,
+// below the data or right of it when horizontal. The spectrum chart may go
+// on the axis side of the data instead.
+type Margins struct {
+	Title       int
+	FreqAxis    int
+	ChannelAxis int
+	BandPlan    int
+	TimeAxis    int
+	ColorBar    int
+	InfoBox     int
+	Occupancy   int
+	Spectrum    int
+}
+
+// DefaultMargins returns margins that fit the annotations in the given font
+// size and orientation, measure gives the width of a string in the font
+func DefaultMargins(fontSize float64, measure func(string) int, orientation string) Margins {
+	lines := func(n float64) int {
+		return textMargin(fontSize, n)
+	}
+
+	if orientation == OrientationHorizontal {
+		return Margins{
+			Title:       0,
+			FreqAxis:    measure("000.000 MHz") + 10,
+			ChannelAxis: measure("ch 000") + 10,
+			BandPlan:    measure("000000000") + 4,
+			TimeAxis:    lines(1.5),
+			ColorBar:    2*colorBarInset + colorBarWidth + measure("-000 dB"),
+			InfoBox:     lines(6),
+			Occupancy:   lines(6),
+			Spectrum:    measure("-000 dB")*3 + 2*chartPad,
+		}
+	}
+
+	return Margins{
+		Title:       0,
+		FreqAxis:    lines(1.5),
+		ChannelAxis: lines(1),
+		BandPlan:    lines(1),
+		TimeAxis:    measure("0000-00-00 00:00:00") + 10,
+		ColorBar:    2*colorBarInset + colorBarWidth + measure("-000 dB"),
+		InfoBox:     lines(6),
+		Occupancy:   lines(6),
+		Spectrum:    lines(10),
+	}
+}
+
+// textMargin is the height of a margin with n lines of text
+func textMargin(fontSize float64, n float64) int {
+	return int(math.Ceil(n*fontSize*spacing)) + 10
+}
+
+// TitleMargin fits the title and the caption lines
+func TitleMargin(fontSize float64, title string, captions []string) int {
+	if title == "" && len(captions) == 0 {
+		return 0
+	}
+
+	lines := float64(len(captions))
+	if title != "" {
+		lines += titleScale
+	}
+
+	return textMargin(fontSize, lines)
+}
+
+// Resolve replaces the automatic margins with the defaults
+func (m Margins) Resolve(defaults Margins) Margins {
+	pick := func(value, def int) int {
+		if value < 0 {
+			return def
+		}
+		return value
+	}
+
+	return Margins{
+		Title:       pick(m.Title, defaults.Title),
+		FreqAxis:    pick(m.FreqAxis, defaults.FreqAxis),
+		ChannelAxis: pick(m.ChannelAxis, defaults.ChannelAxis),
+		BandPlan:    pick(m.BandPlan, defaults.BandPlan),
+		TimeAxis:    pick(m.TimeAxis, defaults.TimeAxis),
+		ColorBar:    pick(m.ColorBar, defaults.ColorBar),
+		InfoBox:     pick(m.InfoBox, defaults.InfoBox),
+		Occupancy:   pick(m.Occupancy, defaults.Occupancy),
+		Spectrum:    pick(m.Spectrum, defaults.Spectrum),
+	}
+}
+
+// LayoutConfig holds the placement options of the layout
+type LayoutConfig struct {
+	Orientation   string // OrientationVertical or OrientationHorizontal
+	NewestFirst   bool   // newest row at the top, or left when horizontal
+	SpectrumAbove bool   // spectrum chart between the axes and the data
+}
+
+// Layout places the data and the annotation areas on the canvas
+type Layout struct {
+	LayoutConfig
+	Margins Margins
+
+	Bounds image.Rectangle // the whole canvas
+	Data   image.Rectangle // the data, one pixel per cell
+
+	bins, rows int
+}
+
+func NewLayout(table *TableComplex, m Margins, conf LayoutConfig) *Layout {
+	return newLayout(table.Bins, table.Integrations, m, conf)
+}
+
+// NewChartLayout is the layout of a chart written to its own file, with
+// the frequency axis at the same place as in a waterfall with the same
+// margins but without any rows of data. The chart goes where the occupancy
+// and spectrum margins are set.
+func NewChartLayout(table *TableComplex, m Margins, orientation string) *Layout {
+	m.InfoBox = 0
+	if orientation != OrientationHorizontal {
+		m.Title, m.ChannelAxis, m.BandPlan = 0, 0, 0
+	}
+	return newLayout(table.Bins, 0, m, LayoutConfig{Orientation: orientation})
+}
+
+// NewPlotLayout is the layout of a plot of the given size that has its own
+// axes rather than a cell per bin and row. The title margin holds the
+// legend, the time axis margin the value labels and the info box margin the
+// time labels. The other margins are not used.
+func NewPlotLayout(width, height int, m Margins) *Layout {
+	m = Margins{Title: m.Title, TimeAxis: m.TimeAxis, ColorBar: m.ColorBar, InfoBox: m.InfoBox}
+	bins := int(math.Max(0, float64(width-m.TimeAxis-m.ColorBar)))
+	rows := int(math.Max(0, float64(height-m.Title-m.InfoBox)))
+	return newLayout(bins, rows, m, LayoutConfig{Orientation: OrientationVertical})
+}
+
+func newLayout(bins, rows int, m Margins, conf LayoutConfig) *Layout {
+	above, below := 0, m.Spectrum
+	if conf.SpectrumAbove {
+		above, below = below, above
+	}
+
+	top := m.Title + m.FreqAxis + m.ChannelAxis + m.BandPlan + above
+	left := m.TimeAxis
+	width, height := bins, rows
+	right, bottom := m.ColorBar, below+m.Occupancy+m.InfoBox
+
+	if conf.Orientation == OrientationHorizontal {
+		top = m.Title + m.TimeAxis
+		left = m.FreqAxis + m.ChannelAxis + m.BandPlan + above
+		width, height = height, width
+		right, bottom = below+m.Occupancy+m.ColorBar, m.InfoBox
+	}
+
+	l := &Layout{
+		LayoutConfig: conf,
+		Margins:      m,
+		Bounds:       image.Rect(0, 0, left+width+right, top+height+bottom),
+		Data:         image.Rect(left, top, left+width, top+height),
+		bins:         bins,
+		rows:         rows,
+	}
+
+	log.WithFields(log.Fields{
+		"canvas":      l.Bounds.String(),
+		"data":        l.Data.String(),
+		"orientation": conf.Orientation,
+		"newestFirst": conf.NewestFirst,
+	}).Debug("layout")
+
+	return l
+}
+
+// Image creates a canvas for the layout filled with the background color
+func (l *Layout) Image(background color.Color) *image.RGBA {
+	img := image.NewRGBA(l.Bounds)
+	draw.Draw(img, l.Bounds, image.NewUniform(background), image.ZP, draw.Src)
+	return img
+}
+
+// Horizontal is true when time runs along the X axis
+func (l *Layout) Horizontal() bool {
+	return l.Orientation == OrientationHorizontal
+}
+
+// DataPoint returns the position on the canvas of the cell at bin x, row y
+func (l *Layout) DataPoint(x, y int) image.Point {
+	if l.Horizontal() {
+		return image.Pt(l.TimePos(y), l.FreqPos(x))
+	}
+	return image.Pt(l.FreqPos(x), l.TimePos(y))
+}
+
+// FreqPos returns the canvas X of a bin, or its Y when horizontal. Bins
+// outside of the table are extrapolated.
+func (l *Layout) FreqPos(bin int) int {
+	if l.Horizontal() {
+		return l.Data.Min.Y + l.bins - 1 - bin
+	}
+	return l.Data.Min.X + bin
+}
+
+// TimePos returns the canvas Y of a row, or its X when horizontal
+func (l *Layout) TimePos(row int) int {
+	if l.NewestFirst {
+		row = l.rows - 1 - row
+	}
+	if l.Horizontal() {
+		return l.Data.Min.X + row
+	}
+	return l.Data.Min.Y + row
+}
+
+// FreqSpan returns the part of across that covers the bins low to high
+func (l *Layout) FreqSpan(low, high int, across image.Rectangle) image.Rectangle {
+	if l.Horizontal() {
+		return image.Rect(across.Min.X, l.FreqPos(high), across.Max.X, l.FreqPos(low)+1).Intersect(across)
+	}
+	return image.Rect(l.FreqPos(low), across.Min.Y, l.FreqPos(high)+1, across.Max.Y).Intersect(across)
+}
+
+// Cells returns the canvas rectangle covering bins low to high of rows start
+// to end
+func (l *Layout) Cells(low, high, start, end int) image.Rectangle {
+	a, b := l.DataPoint(low, start), l.DataPoint(high, end)
+	r := image.Rectangle{Min: a, Max: b}.Canon()
+	r.Max = r.Max.Add(image.Pt(1, 1))
+	return r
+}
+
+// TitleArea is the top of the canvas, above the axes
+func (l *Layout) TitleArea() image.Rectangle {
+	return image.Rect(0, 0, l.Bounds.Max.X, l.Margins.Title)
+}
+
+// FreqAxisArea is below the title, or at the left edge when horizontal
+// including the corner above the data so that labels may be drawn above
+// their guidelines
+func (l *Layout) FreqAxisArea() image.Rectangle {
+	if l.Horizontal() {
+		return image.Rect(0, l.Margins.Title, l.Margins.FreqAxis, l.Data.Max.Y)
+	}
+	return image.Rect(l.Data.Min.X, l.Margins.Title, l.Data.Max.X, l.Margins.Title+l.Margins.FreqAxis)
+}
+
+// ChannelAxisArea is next to the frequency axis, towards the data
+func (l *Layout) ChannelAxisArea() image.Rectangle {
+	if l.Horizontal() {
+		left := l.Margins.FreqAxis
+		return image.Rect(left, l.Data.Min.Y, left+l.Margins.ChannelAxis, l.Data.Max.Y)
+	}
+	top := l.Margins.Title + l.Margins.FreqAxis
+	return image.Rect(l.Data.Min.X, top, l.Data.Max.X, top+l.Margins.ChannelAxis)
+}
+
+// BandPlanArea is between the axes and the data, or the spectrum chart when
+// it is above the data
+func (l *Layout) BandPlanArea() image.Rectangle {
+	above, _ := l.spectrumMargins()
+	if l.Horizontal() {
+		right := l.Data.Min.X - above
+		return image.Rect(right-l.Margins.BandPlan, l.Data.Min.Y, right, l.Data.Max.Y)
+	}
+	bottom := l.Data.Min.Y - above
+	return image.Rect(l.Data.Min.X, bottom-l.Margins.BandPlan, l.Data.Max.X, bottom)
+}
+
+// spectrumMargins splits the spectrum margin in the part above the data and
+// the part below it
+func (l *Layout) spectrumMargins() (above, below int) {
+	if l.SpectrumAbove {
+		return l.Margins.Spectrum, 0
+	}
+	return 0, l.Margins.Spectrum
+}
+
+// SpectrumArea is between the axes and the data when above, or next to the
+// data on the other side
+func (l *Layout) SpectrumArea() image.Rectangle {
+	above, below := l.spectrumMargins()
+	if l.Horizontal() {
+		if above > 0 {
+			return image.Rect(l.Data.Min.X-above, l.Data.Min.Y, l.Data.Min.X, l.Data.Max.Y)
+		}
+		return image.Rect(l.Data.Max.X, l.Data.Min.Y, l.Data.Max.X+below, l.Data.Max.Y)
+	}
+	if above > 0 {
+		return image.Rect(l.Data.Min.X, l.Data.Min.Y-above, l.Data.Max.X, l.Data.Min.Y)
+	}
+	return image.Rect(l.Data.Min.X, l.Data.Max.Y, l.Data.Max.X, l.Data.Max.Y+below)
+}
+
+// TimeAxisArea is left of the data, including the corner above it so that
+// labels may be drawn above their guidelines. When horizontal it is above
+// the data, below the title.
+func (l *Layout) TimeAxisArea() image.Rectangle {
+	if l.Horizontal() {
+		return image.Rect(l.Data.Min.X, l.Margins.Title, l.Data.Max.X, l.Data.Min.Y)
+	}
+	return image.Rect(0, l.Margins.Title, l.Data.Min.X, l.Data.Max.Y)
+}
+
+// ColorBarArea is right of the data and the charts
+func (l *Layout) ColorBarArea() image.Rectangle {
+	_, below := l.spectrumMargins()
+	left := l.Data.Max.X
+	if l.Horizontal() {
+		left += below + l.Margins.Occupancy
+	}
+	return image.Rect(left, l.Data.Min.Y, l.Bounds.Max.X, l.Data.Max.Y)
+}
+
+// OccupancyArea is below the data and a spectrum chart below it, or right
+// of them when horizontal
+func (l *Layout) OccupancyArea() image.Rectangle {
+	_, below := l.spectrumMargins()
+	if l.Horizontal() {
+		left := l.Data.Max.X + below
+		return image.Rect(left, l.Data.Min.Y, left+l.Margins.Occupancy, l.Data.Max.Y)
+	}
+	top := l.Data.Max.Y + below
+	return image.Rect(l.Data.Min.X, top, l.Data.Max.X, top+l.Margins.Occupancy)
+}
+
+// InfoBoxArea is the bottom of the canvas, below the data and the charts
+func (l *Layout) InfoBoxArea() image.Rectangle {
+	_, below := l.spectrumMargins()
+	top := l.Data.Max.Y
+	if !l.Horizontal() {
+		top += below + l.Margins.Occupancy
+	}
+	return image.Rect(0, top, l.Bounds.Max.X, l.Bounds.Max.Y)
+}
@@ -0,0 +1,127 @@
+package gopow
+
+import (
+	"math"
+	"sort"
+)
+
+// resolution of the level histogram, in dB
+const histogramResolution = 0.01
+
+// the level histogram covers at most this window, in dB, samples outside
+// of it, such as glitches, are counted in the edge buckets
+const (
+	histogramFloor   = -300
+	histogramCeiling = 300
+)
+
+// maximum number of buckets, the bucket width grows to fit the range
+const histogramMaxBuckets = 65536
+
+// histogram is a fixed resolution distribution of sample values, used to
+// find percentiles without having to sort every sample in the table
+type histogram struct {
+	low   float64
+	high  float64
+	width float64
+
+	counts []int
+	total  int
+}
+
+func newHistogram(low, high float64) *histogram {
+	low = math.Max(low, histogramFloor)
+	high = math.Min(high, histogramCeiling)
+	if low > high {
+		low, high = math.Min(low, high), math.Max(low, high)
+	}
+
+	width := histogramResolution
+	buckets := int(math.Ceil((high-low)/width)) + 1
+	if buckets > histogramMaxBuckets {
+		buckets = histogramMaxBuckets
+		width = (high - low) / float64(buckets-1)
+	}
+	if buckets < 1 {
+		buckets = 1
+	}
+
+	return &histogram{
+		low:    low,
+		high:   high,
+		width:  width,
+		counts: make([]int, buckets),
+	}
+}
+
+// Add a sample to the histogram, values that are not finite are ignored
+func (h *histogram) Add(value float64) {
+	if !isFinite(value) {
+		return
+	}
+
+	i := int((value - h.low) / h.width)
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(h.counts) {
+		i = len(h.counts) - 1
+	}
+
+	h.counts[i]++
+	h.total++
+}
+
+// Percentile returns the value below which p percent of the samples fall
+func (h *histogram) Percentile(p float64) float64 {
+	if p <= 0 || h.total == 0 {
+		return h.low
+	}
+	if p >= 100 {
+		return h.high
+	}
+
+	target := int(math.Ceil(p / 100 * float64(h.total)))
+
+	sum := 0
+	for i, c := range h.counts {
+		sum += c
+		if sum >= target {
+			value := h.low + (float64(i)+0.5)*h.width
+			return math.Min(value, h.high)
+		}
+	}
+
+	return h.high
+}
+
+func isFinite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
+
+// percentile returns the value below which p percent of the finite values
+// fall, NaN if there are none
+func percentile(values []float64, p float64) float64 {
+	sorted := make([]float64, 0, len(values))
+	for _, v := range values {
+		if isFinite(v) {
+			sorted = append(sorted, v)
+		}
+	}
+
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+
+	sort.Float64s(sorted)
+
+	i := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(sorted) {
+		i = len(sorted) - 1
+	}
+
+	return sorted[i]
+}
@@ -19,6 +19,7 @@ type LineComplex struct {
 	SampleCount int
 
 	Samples []float64
+	Hops    []int // first sample of every hop stitched into the line
 }
 
 type LineSort []*LineComplex
@@ -64,14 +65,15 @@ func NewLineComplex(cells []string) *LineComplex {
 
 	hzLow, _ := strconv.ParseFloat(strings.Trim(cells[2], " "), 64)
 	hzHigh, _ := strconv.ParseFloat(strings.Trim(cells[3], " "), 64)
-	hzStep, _ := strconv.ParseFloat(strings.Trim(cells[3], " "), 64)
-	sc, _ := strconv.ParseInt(strings.Trim(cells[4], " "), 10, 64)
+	hzStep, _ := strconv.ParseFloat(strings.Trim(cells[4], " "), 64)
+	sc, _ := strconv.ParseInt(strings.Trim(cells[5], " "), 10, 64)
 
+	// unparseable samples are kept as missing data
 	samples := []float64{}
 	for _, s := range cells[6:] {
 		sf64, err := strconv.ParseFloat(strings.Trim(s, " "), 64)
 		if err != nil {
-			samples = append(samples, 0)
+			samples = append(samples, math.NaN())
 		} else {
 			samples = append(samples, sf64)
 		}
@@ -103,6 +105,11 @@ func (l *LineComplex) AddSamples(line *LineComplex) {
 		l.Samples = []float64{}
 	}
 
+	if l.Hops == nil {
+		l.Hops = []int{0}
+	}
+	l.Hops = append(l.Hops, len(l.Samples))
+
 	l.Samples = append(l.Samples, line.Samples...)
 
 }
@@ -0,0 +1,143 @@
+package gopow
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Marker labels a known frequency, or a band when Bandwidth is set
+type Marker struct {
+	Hz        float64 `json:"frequency"`
+	Bandwidth float64 `json:"bandwidth,omitempty"`
+	Label     string  `json:"label"`
+	Color     string  `json:"color,omitempty"`
+
+	color color.Color // parsed Color, nil for the default
+}
+
+// HzLow is the lower edge of the marker
+func (m *Marker) HzLow() float64 {
+	return m.Hz - m.Bandwidth/2
+}
+
+// HzHigh is the upper edge of the marker
+func (m *Marker) HzHigh() float64 {
+	return m.Hz + m.Bandwidth/2
+}
+
+// LoadMarkers reads a marker file. Files ending in .json hold a list of
+// markers, any other file is read as CSV with the columns
+// frequency,bandwidth,label,color where all but the frequency are optional.
+// Frequencies may have SI suffixes, empty lines and lines starting with #
+// are skipped, as is a header line.
+func LoadMarkers(file string) ([]*Marker, error) {
+	log.WithFields(log.Fields{
+		"file": file,
+	}).Debug("loading markers")
+
+	var markers []*Marker
+	var err error
+
+	if strings.ToLower(filepath.Ext(file)) == ".json" {
+		markers, err = readMarkersJSON(file)
+	} else {
+		markers, err = readMarkersCSV(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("markers %s: %s", file, err.Error())
+	}
+
+	for _, m := range markers {
+		if m.Color == "" {
+			continue
+		}
+
+		m.color, err = ParseColor(m.Color)
+		if err != nil {
+			return nil, fmt.Errorf("markers %s: %s", file, err.Error())
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"markers": len(markers),
+	}).Debug("markers loaded")
+
+	return markers, nil
+}
+
+func readMarkersJSON(file string) ([]*Marker, error) {
+	buff, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	markers := []*Marker{}
+	err = json.Unmarshal(buff, &markers)
+	if err != nil {
+		return nil, err
+	}
+
+	return markers, nil
+}
+
+func readMarkersCSV(file string) ([]*Marker, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	markers := []*Marker{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		hz, err := ParseHz(record[0])
+		if err != nil {
+			if line == 1 {
+				continue // header
+			}
+			return nil, fmt.Errorf("line %d: %s", line, err.Error())
+		}
+
+		m := &Marker{
+			Hz: hz,
+		}
+
+		if len(record) > 1 && record[1] != "" {
+			m.Bandwidth, err = ParseHz(record[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", line, err.Error())
+			}
+		}
+		if len(record) > 2 {
+			m.Label = record[2]
+		}
+		if len(record) > 3 {
+			m.Color = record[3]
+		}
+
+		markers = append(markers, m)
+	}
+
+	return markers, nil
+}
@@ -0,0 +1,25 @@
+package gopow
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+// NormalizeRows estimates the noise floor of every row as the given
+// percentile of its samples and subtracts it, leaving the power above the
+// noise floor. This removes the slow gain drift of a dongle heating up.
+func (t *TableComplex) NormalizeRows(p float64) {
+	log.WithFields(log.Fields{
+		"percentile": p,
+	}).Debug("normalize rows")
+
+	for _, row := range t.Rows {
+		floor := percentile(row.Samples, p)
+		if !isFinite(floor) {
+			continue
+		}
+
+		for x := range row.Samples {
+			row.Samples[x] -= floor
+		}
+	}
+}
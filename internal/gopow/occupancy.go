@@ -0,0 +1,154 @@
+package gopow
+
+import (
+	"encoding/csv"
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+
+	"github.com/codegangsta/cli"
+	log "github.com/sirupsen/logrus"
+)
+
+// OccupancyBelow attaches the occupancy chart to the waterfall instead of
+// writing it to its own file
+const OccupancyBelow = "below"
+
+// Occupancy is the fraction of time every bin is over the detection
+// threshold, its duty cycle
+type Occupancy struct {
+	Hz        []float64
+	Fraction  []float64 // 0 to 1, NaN for bins without data
+	Threshold []float64
+}
+
+// NewOccupancy counts the rows every bin of the table is over its threshold
+func NewOccupancy(t *TableComplex, conf *DetectConfig) *Occupancy {
+	o := &Occupancy{
+		Hz:        make([]float64, t.Bins),
+		Fraction:  make([]float64, t.Bins),
+		Threshold: Thresholds(t, conf),
+	}
+
+	for x := 0; x < t.Bins; x++ {
+		over, total := 0, 0
+		for _, row := range t.Rows {
+			if x >= len(row.Samples) || math.IsNaN(row.Samples[x]) {
+				continue
+			}
+			total++
+			if row.Samples[x] >= o.Threshold[x] {
+				over++
+			}
+		}
+
+		o.Hz[x] = t.BinHz(x)
+		o.Fraction[x] = float64(over) / float64(total)
+	}
+
+	return o
+}
+
+// Write the occupancy as CSV to file, or stdout when file is empty
+func (o *Occupancy) Write(file string) (err error) {
+	w, err := openOutput(file)
+	if err != nil {
+		return err
+	}
+	defer closeOutput(w, &err)
+
+	if file != "" {
+		log.WithFields(log.Fields{
+			"file": file,
+			"bins": len(o.Hz),
+		}).Info("writing occupancy")
+	}
+
+	writer := csv.NewWriter(w)
+	err = writer.Write([]string{"hz", "occupancy", "threshold"})
+	if err != nil {
+		return err
+	}
+
+	for x := range o.Hz {
+		err = writer.Write([]string{
+			strconv.FormatFloat(o.Hz[x], 'f', 0, 64),
+			strconv.FormatFloat(o.Fraction[x], 'f', 4, 64),
+			strconv.FormatFloat(o.Threshold[x], 'f', 2, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// OccupancyChart draws the occupancy chart in its own image, aligned with
+// the frequency axis of a waterfall with the same margins
+func OccupancyChart(table *TableComplex, o *Occupancy, palette Palette, m Margins, orientation string, conf AnnotatorConfig) (*image.RGBA, error) {
+	m.Spectrum = 0
+	return chartImage(table, palette, m, orientation, conf, func(a *Annotator) {
+		a.DrawOccupancy(o)
+	})
+}
+
+// RunOccupancy loads the input table and writes the occupancy of every bin
+// as CSV, and optionally as a chart
+func RunOccupancy(c *cli.Context) error {
+	input, output, chart := c.String("input"), c.String("output"), c.String("chart")
+	if input == "" {
+		return fmt.Errorf("no input file")
+	}
+
+	config, err := NewDetectConfig(c)
+	if err != nil {
+		return err
+	}
+
+	conf, err := commandRenderConfig(c)
+	if err != nil {
+		return err
+	}
+
+	chartConfig, err := commandChartConfig(c, "occupancy")
+	if err != nil {
+		return err
+	}
+
+	table, err := NewTable(input, conf)
+	if err != nil {
+		return err
+	}
+
+	o := NewOccupancy(table, config)
+
+	err = o.Write(output)
+	if err != nil {
+		return err
+	}
+
+	if chart == "" {
+		return nil
+	}
+
+	size := chartConfig.Annotation.Font.SizeFor(table)
+	measure, err := chartConfig.Annotation.Font.Measurer(size)
+	if err != nil {
+		return err
+	}
+
+	margins := DefaultMargins(size, measure, chartConfig.Orientation)
+	img, err := OccupancyChart(table, o, newPalette(chartConfig.Palette), margins, chartConfig.Orientation, chartConfig.Annotation)
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"file": chart,
+	}).Info("writing occupancy chart")
+
+	return writeImage(img, chart, imageFormat(chart))
+}
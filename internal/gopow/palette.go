@@ -2,26 +2,103 @@ package gopow
 
 import (
 	"image/color"
+	"math"
 
 	"github.com/lucasb-eyer/go-colorful"
 )
 
 type Palette interface {
+	// ColorAt returns the color of a cell in the table
 	ColorAt(table *TableComplex, x, y int) color.Color
+	// Color returns the color of a power value in the tables levels
+	Color(table *TableComplex, power float64) color.Color
+}
+
+// Indicators is a palette with colors of its own for the powers above the
+// max and below the min and for cells without data, for the legend. A nil
+// color is one the palette does not have.
+type Indicators interface {
+	IndicatorColors() (over, under, noData color.Color)
+}
+
+// IndicatorPalette gives cells above the max power, below the min power and
+// cells without data their own colors, so that clipping and data loss show
+// in the image. A nil color falls through to the wrapped palette.
+type IndicatorPalette struct {
+	Palette
+
+	Over   color.Color
+	Under  color.Color
+	NoData color.Color
+}
+
+func (p *IndicatorPalette) IndicatorColors() (color.Color, color.Color, color.Color) {
+	return p.Over, p.Under, p.NoData
+}
+
+func (p *IndicatorPalette) ColorAt(table *TableComplex, x, y int) color.Color {
+	return p.Color(table, table.Rows[y].Sample(x))
+}
+
+func (p *IndicatorPalette) Color(table *TableComplex, power float64) color.Color {
+	switch {
+	case math.IsNaN(power) && p.NoData != nil:
+		return p.NoData
+	case power > *table.Config.MaxPower && p.Over != nil:
+		return p.Over
+	case power < *table.Config.MinPower && p.Under != nil:
+		return p.Under
+	}
+
+	return p.Palette.Color(table, power)
+}
+
+// HighlightPalette colors the cells at or over the threshold of their bin
+// with the wrapped palette and dims the others to gray, so that the cells
+// over the threshold stand out
+type HighlightPalette struct {
+	Palette
+
+	Threshold []float64 // by bin
+}
+
+// IndicatorColors are those of the wrapped palette, the cells they color
+// are kept by the highlight
+func (p *HighlightPalette) IndicatorColors() (color.Color, color.Color, color.Color) {
+	if i, ok := p.Palette.(Indicators); ok {
+		return i.IndicatorColors()
+	}
+	return nil, nil, nil
+}
+
+func (p *HighlightPalette) ColorAt(table *TableComplex, x, y int) color.Color {
+	c := p.Palette.ColorAt(table, x, y)
+
+	power := table.Rows[y].Sample(x)
+	if math.IsNaN(power) || (x < len(p.Threshold) && power >= p.Threshold[x]) {
+		return c
+	}
+
+	// half the luma of the palette color
+	r, g, b, _ := c.RGBA()
+	luma := (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 0xffff
+	return color.Gray{Y: uint8(luma * 0.5 * 0xff)}
 }
 
 type YellowPalette struct {
 }
 
 func (p *YellowPalette) ColorAt(table *TableComplex, x, y int) color.Color {
-	cell := table.Rows[y].Sample(x)
+	return p.Color(table, table.Rows[y].Sample(x))
+}
 
+func (p *YellowPalette) Color(table *TableComplex, power float64) color.Color {
 	hueStart := 0.0
 	hueEnd := 1.0
 
 	span := (*table.Config.MinPower - *table.Config.MaxPower) * -1
 	hPerDeg := (hueStart - hueEnd) / span
-	powNormalized := cell - *table.Config.MinPower
+	powNormalized := power - *table.Config.MinPower
 	powDegrees := powNormalized * hPerDeg
 	hue := hueStart - powDegrees
 
@@ -32,21 +109,23 @@ func (p *YellowPalette) ColorAt(table *TableComplex, x, y int) color.Color {
 		hue = hueEnd
 	}
 
-	return colorful.Color{hue, hue, 0}
+	return colorful.Color{R: hue, G: hue, B: 0}
 }
 
 type SpectrumPalette struct {
 }
 
 func (p *SpectrumPalette) ColorAt(table *TableComplex, x, y int) color.Color {
-	cell := table.Rows[y].Sample(x)
+	return p.Color(table, table.Rows[y].Sample(x))
+}
 
+func (p *SpectrumPalette) Color(table *TableComplex, power float64) color.Color {
 	hueStart := 236.0
 	hueEnd := 0.0
 
 	span := (*table.Config.MinPower - *table.Config.MaxPower) * -1
 	hPerDeg := (hueStart - hueEnd) / span
-	powNormalized := cell - *table.Config.MinPower
+	powNormalized := power - *table.Config.MinPower
 	powDegrees := powNormalized * hPerDeg
 	hue := hueStart - powDegrees
 
@@ -59,3 +138,37 @@ func (p *SpectrumPalette) ColorAt(table *TableComplex, x, y int) color.Color {
 
 	return colorful.Hsv(hue, 1, 0.90)
 }
+
+// DivergingPalette is centered on 0 dB, negative values are blue and
+// positive red. Useful for baseline subtracted and difference tables.
+type DivergingPalette struct {
+}
+
+func (p *DivergingPalette) ColorAt(table *TableComplex, x, y int) color.Color {
+	return p.Color(table, table.Rows[y].Sample(x))
+}
+
+func (p *DivergingPalette) Color(table *TableComplex, power float64) color.Color {
+	low := colorful.Color{R: 0.23, G: 0.30, B: 0.75}
+	mid := colorful.Color{R: 0.95, G: 0.95, B: 0.95}
+	high := colorful.Color{R: 0.71, G: 0.02, B: 0.15}
+
+	// symmetric around 0 so that equal magnitudes get equal saturation
+	span := math.Max(math.Abs(*table.Config.MinPower), math.Abs(*table.Config.MaxPower))
+	if span == 0 {
+		return mid
+	}
+
+	t := power / span
+	if t > 1 {
+		t = 1
+	}
+	if t < -1 {
+		t = -1
+	}
+
+	if t < 0 {
+		return mid.BlendLab(low, -t).Clamped()
+	}
+	return mid.BlendLab(high, t).Clamped()
+}
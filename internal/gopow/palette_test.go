@@ -0,0 +1,62 @@
+package gopow
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestIndicatorColorsThroughHighlight(t *testing.T) {
+	over, under, noData := color.RGBA{R: 0xff, A: 0xff}, color.RGBA{B: 0xff, A: 0xff}, color.Gray{Y: 0x80}
+
+	var palette Palette = &HighlightPalette{
+		Palette: &IndicatorPalette{
+			Palette: &SpectrumPalette{},
+			Over:    over,
+			Under:   under,
+			NoData:  noData,
+		},
+	}
+
+	i, ok := palette.(Indicators)
+	if !ok {
+		t.Fatal("highlight palette without indicator colors")
+	}
+	if o, u, n := i.IndicatorColors(); o != over || u != under || n != noData {
+		t.Errorf("indicator colors %v %v %v, want %v %v %v", o, u, n, over, under, noData)
+	}
+
+	plain := &HighlightPalette{Palette: &SpectrumPalette{}}
+	if o, u, n := plain.IndicatorColors(); o != nil || u != nil || n != nil {
+		t.Errorf("indicator colors %v %v %v of a palette without them", o, u, n)
+	}
+}
+
+func TestIndicatorPaletteColor(t *testing.T) {
+	min, max := -40.0, -10.0
+	table := &TableComplex{Config: &RenderConfig{MinPower: &min, MaxPower: &max}}
+
+	over, under, noData := color.RGBA{R: 0xff, A: 0xff}, color.RGBA{B: 0xff, A: 0xff}, color.Gray{Y: 0x80}
+	p := &IndicatorPalette{Palette: &SpectrumPalette{}, Over: over, Under: under, NoData: noData}
+
+	tests := []struct {
+		power float64
+		want  color.Color
+	}{
+		{math.NaN(), noData},
+		{math.Inf(1), over},
+		{-5, over},
+		{math.Inf(-1), under},
+		{-45, under},
+	}
+
+	for _, test := range tests {
+		if got := p.Color(table, test.power); got != test.want {
+			t.Errorf("Color(%g) = %v, want %v", test.power, got, test.want)
+		}
+	}
+
+	if got, want := p.Color(table, -20), (&SpectrumPalette{}).Color(table, -20); got != want {
+		t.Errorf("Color(-20) = %v, want the wrapped palette %v", got, want)
+	}
+}
@@ -0,0 +1,180 @@
+package gopow
+
+import (
+	"encoding/csv"
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	SpectrumAbove = "above" // attach the spectrum chart between the axes and the data
+	SpectrumBelow = "below" // attach the spectrum chart below the data
+)
+
+// Spectrum holds the max, mean, median and min of every bin over a window of
+// rows, in dB
+type Spectrum struct {
+	Hz     []float64
+	Max    []float64
+	Mean   []float64 // mean of the power, not of the dB values
+	Median []float64
+	Min    []float64
+
+	From time.Time
+	To   time.Time
+}
+
+// NewSpectrum computes the spectrum over the rows from and before to, a nil
+// time is the start or the end of the table
+func NewSpectrum(t *TableComplex, from, to *time.Time) *Spectrum {
+	start, end := 0, t.rowCount()
+	if from != nil {
+		start = t.TimeRow(*from)
+	}
+	if to != nil && t.TimeRow(*to) < end {
+		end = t.TimeRow(*to)
+	}
+	if end < start {
+		end = start
+	}
+
+	s := &Spectrum{
+		Hz:     make([]float64, t.Bins),
+		Max:    make([]float64, t.Bins),
+		Mean:   make([]float64, t.Bins),
+		Median: make([]float64, t.Bins),
+		Min:    make([]float64, t.Bins),
+	}
+
+	if start < end {
+		s.From, s.To = *t.Rows[start].Time, *t.Rows[end-1].Time
+	}
+
+	column := make([]float64, 0, end-start)
+	for x := 0; x < t.Bins; x++ {
+		column = column[:0]
+		for _, row := range t.Rows[start:end] {
+			if x < len(row.Samples) && isFinite(row.Samples[x]) {
+				column = append(column, row.Samples[x])
+			}
+		}
+
+		s.Hz[x] = t.BinHz(x)
+		s.Max[x] = percentile(column, 100)
+		s.Median[x] = percentile(column, 50)
+		s.Min[x] = percentile(column, 0)
+
+		linear := 0.0
+		for _, v := range column {
+			linear += math.Pow(10, v/10)
+		}
+		s.Mean[x] = 10 * math.Log10(linear/float64(len(column)))
+	}
+
+	log.WithFields(log.Fields{
+		"from": s.From.String(),
+		"to":   s.To.String(),
+		"rows": end - start,
+	}).Debug("spectrum")
+
+	return s
+}
+
+// ParseWindowTime reads a time as "2006-01-02 15:04:05" in UTC, or as a
+// duration since start
+func ParseWindowTime(str string, start time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(str); err == nil {
+		return start.Add(d), nil
+	}
+
+	t, err := time.Parse("2006-01-02 15:04:05", str)
+	if err != nil {
+		return t, fmt.Errorf("invalid time: %s", str)
+	}
+
+	return t, nil
+}
+
+// isDuration tells a window time given as a duration since the scan start
+func isDuration(str string) bool {
+	_, err := time.ParseDuration(str)
+	return err == nil
+}
+
+// Write the spectrum as CSV to file, or stdout when file is empty
+func (s *Spectrum) Write(file string) (err error) {
+	w, err := openOutput(file)
+	if err != nil {
+		return err
+	}
+	defer closeOutput(w, &err)
+
+	if file != "" {
+		log.WithFields(log.Fields{
+			"file": file,
+			"bins": len(s.Hz),
+		}).Info("writing spectrum")
+	}
+
+	format := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	}
+
+	writer := csv.NewWriter(w)
+	err = writer.Write([]string{"hz", "max", "mean", "median", "min"})
+	if err != nil {
+		return err
+	}
+
+	for x := range s.Hz {
+		err = writer.Write([]string{
+			strconv.FormatFloat(s.Hz[x], 'f', 0, 64),
+			format(s.Max[x]),
+			format(s.Mean[x]),
+			format(s.Median[x]),
+			format(s.Min[x]),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// SpectrumChart draws the spectrum chart in its own image, aligned with the
+// frequency axis of a waterfall with the same margins
+func SpectrumChart(table *TableComplex, s *Spectrum, palette Palette, m Margins, orientation string, conf AnnotatorConfig) (*image.RGBA, error) {
+	m.Occupancy = 0
+	return chartImage(table, palette, m, orientation, conf, func(a *Annotator) {
+		a.DrawSpectrum(s)
+	})
+}
+
+// chartImage creates the image of a chart written to its own file, with the
+// frequency axis drawn by DrawXScale and the chart by draw
+func chartImage(table *TableComplex, palette Palette, m Margins, orientation string, conf AnnotatorConfig, draw func(a *Annotator)) (*image.RGBA, error) {
+	theme, err := ResolveTheme(conf.Theme, palette, table)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := NewChartLayout(table, m, orientation)
+	img := layout.Image(theme.Background)
+
+	annotator, err := NewAnnotator(img, table, palette, layout, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	annotator.DrawXScale()
+	draw(annotator)
+
+	return img, nil
+}
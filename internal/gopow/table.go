@@ -2,6 +2,7 @@ package gopow
 
 import (
 	"bytes"
+	"fmt"
 	"image"
 	"io/ioutil"
 	"math"
@@ -19,20 +20,44 @@ type TableComplex struct {
 
 	Rows []*LineComplex
 
-	Bins         int // horizontal slots, columns, bandwidth
-	Integrations int // vertical slots, rows
+	Bins         int   // horizontal slots, columns, bandwidth
+	Integrations int   // vertical slots, rows
+	Hops         []int // first bin of every hop
 
 	HzLow  float64 // X Scale start
 	HzHigh float64 // X Scale end
 
 	TimeStart *time.Time // real time, Y Scale
 	TimeEnd   *time.Time
+
+	autoMin bool // MinPower was picked from MinPercentile
+	autoMax bool // MaxPower was picked from MaxPercentile
 }
 
 // RenderConfig overrides automaticly calculated defaults
 type RenderConfig struct {
 	MinPower *float64 // minimum power value, used for color rendering
 	MaxPower *float64 // maximum dito
+
+	MinPercentile float64 // percentile used for MinPower when not set
+	MaxPercentile float64 // percentile used for MaxPower when not set
+
+	NormalizeRows      bool    // subtract the noise floor of each row
+	RowFloorPercentile float64 // percentile of a row taken as its noise floor
+
+	Baseline     string // subtract a baseline, BaselineMedian or a file
+	SaveBaseline string // write the subtracted baseline to this file
+
+	HopCrop float64 // percent of bins replaced at both edges of every hop
+	DCBins  int     // bins interpolated over at the center of every hop
+
+	Tuning *TuningConfig // RF frequencies of the tuned ones, nil as tuned
+
+	EqualizeHops       bool    // offset every hop to a common noise floor
+	HopFloorPercentile float64 // percentile of a hop taken as its noise floor
+
+	Compare string // subtract this capture, see Diff
+	Align   string // AlignRelative or AlignOverlap
 }
 
 func NewTable(file string, conf *RenderConfig) (*TableComplex, error) {
@@ -47,6 +72,53 @@ func NewTable(file string, conf *RenderConfig) (*TableComplex, error) {
 		return nil, err
 	}
 
+	if conf.Compare != "" {
+		other := &TableComplex{
+			Config: conf,
+		}
+
+		err = other.Load(conf.Compare)
+		if err != nil {
+			return nil, err
+		}
+
+		err = t.Diff(other, conf.Align)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.EqualizeHops {
+		t.EqualizeHops(conf.HopFloorPercentile)
+	}
+
+	if conf.NormalizeRows {
+		t.NormalizeRows(conf.RowFloorPercentile)
+	}
+
+	if conf.Baseline != "" {
+		var baseline *Baseline
+		if conf.Baseline == BaselineMedian {
+			baseline = NewBaseline(t)
+		} else {
+			baseline, err = LoadBaseline(conf.Baseline)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if conf.SaveBaseline != "" {
+			err = baseline.Write(conf.SaveBaseline)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		t.SubtractBaseline(baseline)
+	}
+
+	t.resolveLevels()
+
 	return t, nil
 }
 
@@ -67,13 +139,14 @@ func (t *TableComplex) Load(file string) error {
 
 	t.Rows = t.parseBuffer(buff)
 
+	if t.Config.Tuning != nil {
+		return t.Retune(t.Config.Tuning)
+	}
+
 	return nil
 }
 
 func (t *TableComplex) parseBuffer(filebuffer []byte) []*LineComplex {
-	var max = float64(math.MaxFloat64 * -1)
-	var min = float64(math.MaxFloat64)
-
 	lines := bytes.Split(filebuffer, []byte("\n"))
 
 	table := map[string][]*LineComplex{}
@@ -97,16 +170,6 @@ func (t *TableComplex) parseBuffer(filebuffer []byte) []*LineComplex {
 		if row != nil {
 			rows = append(rows, row)
 
-			if min > row.LowSample() {
-				min = row.LowSample()
-			}
-			if max < row.HighSample() {
-				max = row.HighSample()
-			}
-
-			t.HzLow = row.HzLow
-			t.HzHigh = row.HzHigh
-
 			if row.Time != nil {
 				if t.TimeStart == nil {
 					t.TimeStart = row.Time
@@ -129,23 +192,20 @@ func (t *TableComplex) parseBuffer(filebuffer []byte) []*LineComplex {
 
 	sort.Sort(LineSort(rows))
 
-	if t.Config.MaxPower == nil {
-		t.Config.MaxPower = &max
-	}
-
-	if t.Config.MinPower == nil {
-		t.Config.MinPower = &min
-	}
-
-	log.WithFields(log.Fields{
-		"pMax": *t.Config.MaxPower,
-		"pMin": *t.Config.MinPower,
-	}).Debug("integrated lines")
-
 	t.Integrations = len(rows)
 
 	if t.Integrations > 0 {
-		t.Bins = len(rows[0].Samples)
+		// the first and last rows are often cut short by the start and end
+		// of the scan, the fullest row has the whole band and all the hops
+		fullest := rows[0]
+		for _, row := range rows {
+			if len(row.Samples) > len(fullest.Samples) {
+				fullest = row
+			}
+		}
+		t.Bins = len(fullest.Samples)
+		t.Hops = append([]int(nil), fullest.Hops...)
+		t.HzLow, t.HzHigh = fullest.HzLow, fullest.HzHigh
 	} else {
 		log.Fatal("no samples found")
 	}
@@ -153,11 +213,94 @@ func (t *TableComplex) parseBuffer(filebuffer []byte) []*LineComplex {
 	log.WithFields(log.Fields{
 		"bins":         t.Bins,
 		"integrations": t.Integrations,
+		"hops":         len(t.Hops),
 	}).Debug("parsed table")
 
 	return rows
 }
 
+// resolveLevels picks the power levels not set in the config from the
+// configured percentiles of all samples in the table
+func (t *TableComplex) resolveLevels() {
+	if t.Config.MinPower != nil && t.Config.MaxPower != nil {
+		return
+	}
+
+	var max = float64(math.MaxFloat64 * -1)
+	var min = float64(math.MaxFloat64)
+
+	for _, row := range t.Rows {
+		if min > row.LowSample() {
+			min = row.LowSample()
+		}
+		if max < row.HighSample() {
+			max = row.HighSample()
+		}
+	}
+
+	if min > max {
+		min, max = 0, 0
+	}
+
+	hist := newHistogram(min, max)
+	for _, row := range t.Rows {
+		for _, sample := range row.Samples {
+			hist.Add(sample)
+		}
+	}
+
+	if t.Config.MinPower == nil {
+		pMin := hist.Percentile(t.Config.MinPercentile)
+		t.Config.MinPower = &pMin
+		t.autoMin = true
+	}
+
+	if t.Config.MaxPower == nil {
+		pMax := hist.Percentile(t.Config.MaxPercentile)
+		t.Config.MaxPower = &pMax
+		t.autoMax = true
+	}
+
+	log.WithFields(log.Fields{
+		"pMax":        *t.Config.MaxPower,
+		"pMin":        *t.Config.MinPower,
+		"percentiles": fmt.Sprintf("%g-%g", t.Config.MinPercentile, t.Config.MaxPercentile),
+	}).Info("power levels")
+}
+
+// rowCount is the number of rows with a time, leaving out a trailing empty
+// row, the rows without a time are sorted last
+func (t *TableComplex) rowCount() int {
+	return sort.Search(len(t.Rows), func(i int) bool {
+		return t.Rows[i].Time == nil
+	})
+}
+
+// TimeRow returns the first row at or after the time, the rows are sorted
+// by time with rows without a time last
+func (t *TableComplex) TimeRow(at time.Time) int {
+	return sort.Search(len(t.Rows), func(i int) bool {
+		rt := t.Rows[i].Time
+		return rt == nil || !rt.Before(at)
+	})
+}
+
+// BinHz returns the center frequency of bin x
+func (t *TableComplex) BinHz(x int) float64 {
+	return t.HzLow + (float64(x)+0.5)*t.HzPerBin()
+}
+
+// HzBin returns the bin containing the frequency hz, the bin is outside of
+// the table for frequencies outside of HzLow..HzHigh
+func (t *TableComplex) HzBin(hz float64) int {
+	return int(math.Floor((hz - t.HzLow) / t.HzPerBin()))
+}
+
+// HzPerBin is the bandwidth of a single bin
+func (t *TableComplex) HzPerBin() float64 {
+	return (t.HzHigh - t.HzLow) / float64(t.Bins)
+}
+
 func (t *TableComplex) Image() *image.RGBA {
 	log.WithFields(log.Fields{
 		"width":  t.Bins,
@@ -172,12 +315,23 @@ func (t *TableComplex) IntegrateLines(lines []*LineComplex) *LineComplex {
 		return nil
 	}
 
+	// hops in frequency order, each with its DC spike removed
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].HzLow < lines[j].HzLow
+	})
+	for _, l := range lines {
+		l.InterpolateDC(t.Config.DCBins)
+	}
+
 	masterline := lines[0]
+	masterline.Hops = []int{0}
 	for i, l := range lines {
 		if i > 0 {
 			masterline.AddSamples(l)
 		}
 	}
 
+	masterline.CropHopEdges(t.Config.HopCrop)
+
 	return masterline
 }
@@ -0,0 +1,79 @@
+package gopow
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// sweepCSV builds rtl_power lines for the given times, each with the hops
+// of 4 bins from 88 MHz up, hops[i] hops in sweep i
+func sweepCSV(hops ...int) string {
+	b := &strings.Builder{}
+	for i, n := range hops {
+		for h := 0; h < n; h++ {
+			low := 88e6 + float64(h)*2e6
+			fmt.Fprintf(b, "2016-03-01, 23:%02d:00, %.0f, %.0f, 500000.00, 16, -40.%d, -41.%d, -42.%d, -43.%d\n",
+				i, low, low+2e6, h, h, h, h)
+		}
+	}
+	return b.String()
+}
+
+func TestParseBufferPartialSweep(t *testing.T) {
+	buff := []byte(sweepCSV(2, 2, 1))
+
+	// the rows are integrated in map order, repeat to cover the orders
+	for i := 0; i < 40; i++ {
+		table := &TableComplex{Config: &RenderConfig{}}
+		table.Rows = table.parseBuffer(buff)
+
+		if table.HzLow != 88e6 || table.HzHigh != 92e6 {
+			t.Fatalf("span %g-%g, want 88e6-92e6", table.HzLow, table.HzHigh)
+		}
+		if table.Bins != 8 {
+			t.Fatalf("bins %d, want 8", table.Bins)
+		}
+		if len(table.Hops) != 2 || table.Hops[0] != 0 || table.Hops[1] != 4 {
+			t.Fatalf("hops %v, want [0 4]", table.Hops)
+		}
+		if n := table.rowCount(); n != 3 {
+			t.Fatalf("timed rows %d, want 3", n)
+		}
+		if table.HzPerBin() != 500000 {
+			t.Fatalf("hz per bin %g, want 500000", table.HzPerBin())
+		}
+	}
+}
+
+func TestParseBufferFirstRowHops(t *testing.T) {
+	table := &TableComplex{Config: &RenderConfig{}}
+	table.Rows = table.parseBuffer([]byte(sweepCSV(1, 2)))
+
+	// the table hops are a copy, not the slice of a row
+	table.Hops[0] = -1
+	for _, row := range table.Rows[:table.rowCount()] {
+		if len(row.Hops) > 0 && row.Hops[0] != 0 {
+			t.Fatalf("row hops changed with the table hops: %v", row.Hops)
+		}
+	}
+}
+
+func TestNewLineComplex(t *testing.T) {
+	line := NewLineComplex(strings.Split("2016-03-01, 23:55:00, 88000000, 90000000, 10000.00, 16, -44.38, x, -43.23", ","))
+
+	if line.Time == nil || line.Time.Format("2006-01-02 15:04:05") != "2016-03-01 23:55:00" {
+		t.Fatalf("time %v", line.Time)
+	}
+	if line.HzLow != 88e6 || line.HzHigh != 90e6 || line.HzStep != 10000 || line.SampleCount != 16 {
+		t.Fatalf("line %g-%g step %g count %d", line.HzLow, line.HzHigh, line.HzStep, line.SampleCount)
+	}
+	if len(line.Samples) != 3 || line.Samples[0] != -44.38 || !math.IsNaN(line.Samples[1]) {
+		t.Fatalf("samples %v", line.Samples)
+	}
+
+	if blank := NewLineComplex([]string{""}); blank.Time != nil {
+		t.Fatalf("blank line with a time")
+	}
+}
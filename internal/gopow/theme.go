@@ -0,0 +1,75 @@
+package gopow
+
+import (
+	"fmt"
+	"image/color"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	ThemeAuto  = "auto"  // pick the theme that contrasts with the palette
+	ThemeDark  = "dark"  // light text on dark margins
+	ThemeLight = "light" // dark text on light margins
+
+	TextPlain   = "plain"
+	TextOutline = "outline" // a halo around every glyph
+	TextBox     = "box"     // a semi-transparent box behind the text
+)
+
+// Theme holds the colors of the annotations
+type Theme struct {
+	Name       string
+	Background color.Color // margins
+	Text       color.Color // text and guidelines
+	Halo       color.Color // outlines and text boxes
+}
+
+var themes = map[string]Theme{
+	ThemeDark: {
+		Name:       ThemeDark,
+		Background: color.Black,
+		Text:       color.White,
+		Halo:       color.Black,
+	},
+	ThemeLight: {
+		Name:       ThemeLight,
+		Background: color.White,
+		Text:       color.Black,
+		Halo:       color.White,
+	},
+}
+
+// ResolveTheme returns the named theme, the auto theme is light when the
+// palette is mostly bright over the power levels of the table
+func ResolveTheme(name string, palette Palette, table *TableComplex) (Theme, error) {
+	if name != ThemeAuto {
+		theme, ok := themes[name]
+		if !ok {
+			return Theme{}, fmt.Errorf("unsupported theme: %s", name)
+		}
+		return theme, nil
+	}
+
+	min, max := *table.Config.MinPower, *table.Config.MaxPower
+
+	const steps = 32
+	lightness := 0.0
+	for i := 0; i < steps; i++ {
+		power := min + (max-min)*float64(i)/float64(steps-1)
+		l, _, _ := toColorful(palette.Color(table, power)).Lab()
+		lightness += l / steps
+	}
+
+	theme := themes[ThemeDark]
+	if lightness > 0.75 {
+		theme = themes[ThemeLight]
+	}
+
+	log.WithFields(log.Fields{
+		"lightness": lightness,
+		"theme":     theme.Name,
+	}).Debug("auto theme")
+
+	return theme, nil
+}
@@ -0,0 +1,232 @@
+package gopow
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// niceStep returns a step of 1, 2 or 5 times a power of ten that divides
+// span into at most count parts
+func niceStep(span float64, count int) float64 {
+	if count < 1 {
+		count = 1
+	}
+	if span <= 0 {
+		return 1
+	}
+
+	raw := span / float64(count)
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+
+	for _, m := range []float64{1, 2, 5, 10} {
+		if m*magnitude >= raw {
+			return m * magnitude
+		}
+	}
+
+	return 10 * magnitude
+}
+
+// tickValues returns every multiple of step within low..high
+func tickValues(low, high, step float64) []float64 {
+	values := []float64{}
+	for i := math.Ceil(low / step); i*step <= high; i++ {
+		v := i * step
+		if v == 0 {
+			v = 0 // no negative zero labels
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+// tick is a mark on an axis, minor ticks have no label
+type tick struct {
+	offset int // bin or row in the table
+	value  float64
+	label  string
+	major  bool
+}
+
+// minorStep divides a nice step into 4 or 5 minor steps
+func minorStep(step float64) float64 {
+	mantissa := step / math.Pow(10, math.Floor(math.Log10(step)))
+	if math.Abs(mantissa-2) < 1e-6 {
+		return step / 4
+	}
+	return step / 5
+}
+
+// siPrefixes from Hz to GHz
+var siPrefixes = []struct {
+	scale  float64
+	prefix string
+}{
+	{1, ""},
+	{1e3, "k"},
+	{1e6, "M"},
+	{1e9, "G"},
+}
+
+// freqFormat picks the SI unit and precision for labels step Hz apart.
+// The unit is the largest that keeps the step within three decimals, but
+// never larger than the unit of the frequencies themselves.
+func freqFormat(step, maxHz float64) func(hz float64) string {
+	unit := siPrefixes[0]
+	for _, p := range siPrefixes {
+		if p.scale <= step*1000 && p.scale <= math.Abs(maxHz) {
+			unit = p
+		}
+	}
+
+	decimals := int(-math.Floor(math.Log10(step/unit.scale) + 1e-9))
+	if decimals < 0 {
+		decimals = 0
+	}
+
+	return func(hz float64) string {
+		return fmt.Sprintf("%.*f %sHz", decimals, hz/unit.scale, unit.prefix)
+	}
+}
+
+// freqTicks returns the major and minor frequency ticks for the table, with
+// at most count labels
+func freqTicks(table *TableComplex, count int) []tick {
+	step := niceStep(table.HzHigh-table.HzLow, count)
+	minor := minorStep(step)
+	format := freqFormat(step, table.HzHigh)
+
+	ticks := []tick{}
+	for _, hz := range tickValues(table.HzLow, table.HzHigh, minor) {
+		bin := table.HzBin(hz)
+		if bin < 0 || bin >= table.Bins {
+			continue
+		}
+
+		// the minor values are generated from integer multiples, a major
+		// tick is a minor one that is a multiple of step as well
+		major := math.Abs(math.Remainder(hz, step)) < minor/2
+
+		t := tick{
+			offset: bin,
+			value:  hz,
+			major:  major,
+		}
+		if major {
+			t.label = format(hz)
+		}
+
+		ticks = append(ticks, t)
+	}
+
+	return ticks
+}
+
+const (
+	TimeLabelsAbsolute = "absolute" // wall clock time, with the date at rollovers
+	TimeLabelsRelative = "relative" // T+hh:mm:ss since the scan start
+)
+
+// round intervals for time ticks
+var timeSteps = []time.Duration{
+	time.Second,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	15 * time.Second,
+	30 * time.Second,
+	time.Minute,
+	2 * time.Minute,
+	5 * time.Minute,
+	10 * time.Minute,
+	15 * time.Minute,
+	30 * time.Minute,
+	time.Hour,
+	2 * time.Hour,
+	3 * time.Hour,
+	6 * time.Hour,
+	12 * time.Hour,
+	24 * time.Hour,
+}
+
+// timeStep returns the smallest round interval that divides span into at
+// most count parts
+func timeStep(span time.Duration, count int) time.Duration {
+	if count < 1 {
+		count = 1
+	}
+
+	for _, step := range timeSteps {
+		if span/step <= time.Duration(count) {
+			return step
+		}
+	}
+
+	// multiple days
+	days := span / (24 * time.Hour) / time.Duration(count)
+	return (days + 1) * 24 * time.Hour
+}
+
+// relativeTime formats a duration as T+hh:mm:ss
+func relativeTime(d time.Duration) string {
+	secs := int64(d / time.Second)
+	return fmt.Sprintf("T+%02d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+}
+
+// timeTicks returns the time ticks for the table with at most count labels.
+// Ticks are placed on the first row at or after the tick time, using the
+// real timestamps of the rows.
+func timeTicks(table *TableComplex, count int, labels string) []tick {
+	if table.TimeStart == nil || table.TimeEnd == nil {
+		return []tick{}
+	}
+
+	start, end := *table.TimeStart, *table.TimeEnd
+	step := timeStep(end.Sub(start), count)
+
+	clock := "15:04"
+	if step < time.Minute {
+		clock = "15:04:05"
+	}
+
+	ticks := []tick{}
+	lastDate := ""
+
+	// relative ticks are round from the start, absolute ones on the clock
+	first := start.Truncate(step)
+	if labels == TimeLabelsRelative {
+		first = start
+	}
+	if first.Before(start) {
+		first = first.Add(step)
+	}
+
+	for at := first; !at.After(end); at = at.Add(step) {
+		row := table.TimeRow(at)
+		if row >= table.Integrations {
+			break
+		}
+
+		var label string
+		switch labels {
+		case TimeLabelsRelative:
+			label = relativeTime(at.Sub(start))
+		default:
+			label = at.Format(clock)
+			if date := at.Format("2006-01-02"); date != lastDate {
+				label = date + " " + label
+				lastDate = date
+			}
+		}
+
+		ticks = append(ticks, tick{
+			offset: row,
+			value:  float64(at.Unix()),
+			label:  label,
+			major:  true,
+		})
+	}
+
+	return ticks
+}
@@ -0,0 +1,232 @@
+package gopow
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNiceStep(t *testing.T) {
+	tests := []struct {
+		span  float64
+		count int
+		want  float64
+	}{
+		{30, 6, 5},
+		{30, 3, 10},
+		{30, 4, 10},
+		{30, 0, 50},
+		{1, 10, 0.1},
+		{0.7, 3, 0.5},
+		{6e6, 6, 1e6},
+		{6e6, 4, 2e6},
+		{0, 5, 1},
+		{-3, 5, 1},
+	}
+
+	for _, test := range tests {
+		if got := niceStep(test.span, test.count); got != test.want {
+			t.Errorf("niceStep(%g, %d) = %g, want %g", test.span, test.count, got, test.want)
+		}
+	}
+}
+
+func TestTickValues(t *testing.T) {
+	tests := []struct {
+		low, high, step float64
+		want            []float64
+	}{
+		{-42, -8, 10, []float64{-40, -30, -20, -10}},
+		{-10, 10, 10, []float64{-10, 0, 10}},
+		{1, 9, 10, []float64{}},
+		{88e6, 90e6, 5e5, []float64{88e6, 88.5e6, 89e6, 89.5e6, 90e6}},
+	}
+
+	for _, test := range tests {
+		if got := tickValues(test.low, test.high, test.step); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("tickValues(%g, %g, %g) = %v, want %v", test.low, test.high, test.step, got, test.want)
+		}
+	}
+}
+
+func TestMinorStep(t *testing.T) {
+	tests := []struct {
+		step, want float64
+	}{
+		{1, 0.2},
+		{2, 0.5},
+		{5, 1},
+		{200e3, 50e3},
+		{10, 2},
+	}
+
+	for _, test := range tests {
+		if got := minorStep(test.step); got != test.want {
+			t.Errorf("minorStep(%g) = %g, want %g", test.step, got, test.want)
+		}
+	}
+}
+
+func TestFreqFormat(t *testing.T) {
+	tests := []struct {
+		step, maxHz, hz float64
+		want            string
+	}{
+		{1e6, 92e6, 89e6, "89 MHz"},
+		{1e5, 92e6, 88.5e6, "88.5 MHz"},
+		{2e3, 1e6, 5e5, "0.500 MHz"},
+		{500e6, 1.7e9, 1.5e9, "1.5 GHz"},
+		{10, 500, 120, "120 Hz"},
+	}
+
+	for _, test := range tests {
+		if got := freqFormat(test.step, test.maxHz)(test.hz); got != test.want {
+			t.Errorf("freqFormat(%g, %g)(%g) = %q, want %q", test.step, test.maxHz, test.hz, got, test.want)
+		}
+	}
+}
+
+func TestFreqTicks(t *testing.T) {
+	table := &TableComplex{Config: &RenderConfig{}}
+	table.Rows = table.parseBuffer([]byte(sweepCSV(2, 2)))
+
+	// 88-92 MHz in 8 bins of 500 kHz, a label every MHz and a minor tick
+	// every 200 kHz
+	ticks := freqTicks(table, 4)
+
+	labels, offsets, minors := []string{}, []int{}, 0
+	for _, tick := range ticks {
+		if tick.major {
+			labels = append(labels, tick.label)
+			offsets = append(offsets, tick.offset)
+		} else {
+			minors++
+			if tick.label != "" {
+				t.Errorf("minor tick at %g with label %q", tick.value, tick.label)
+			}
+		}
+	}
+
+	if want := []string{"88 MHz", "89 MHz", "90 MHz", "91 MHz"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("labels %v, want %v", labels, want)
+	}
+	if want := []int{0, 2, 4, 6}; !reflect.DeepEqual(offsets, want) {
+		t.Errorf("offsets %v, want %v", offsets, want)
+	}
+	if minors != 16 {
+		t.Errorf("%d minor ticks, want 16", minors)
+	}
+}
+
+// minuteTable is a table with a row every step from start
+func minuteTable(start time.Time, step time.Duration, rows int) *TableComplex {
+	table := &TableComplex{Config: &RenderConfig{}, Bins: 1, Integrations: rows}
+	for y := 0; y < rows; y++ {
+		at := start.Add(time.Duration(y) * step)
+		table.Rows = append(table.Rows, &LineComplex{Time: &at, Samples: []float64{-40}})
+	}
+	table.TimeStart, table.TimeEnd = table.Rows[0].Time, table.Rows[rows-1].Time
+	return table
+}
+
+func TestTimeStep(t *testing.T) {
+	tests := []struct {
+		span  time.Duration
+		count int
+		want  time.Duration
+	}{
+		{59 * time.Minute, 6, 10 * time.Minute},
+		{20 * time.Minute, 4, 5 * time.Minute},
+		{90 * time.Second, 10, 10 * time.Second},
+		{7 * time.Hour, 0, 6 * time.Hour},
+		{3 * time.Hour, 1, 2 * time.Hour},
+		{10 * 24 * time.Hour, 2, 6 * 24 * time.Hour},
+	}
+
+	for _, test := range tests {
+		if got := timeStep(test.span, test.count); got != test.want {
+			t.Errorf("timeStep(%s, %d) = %s, want %s", test.span, test.count, got, test.want)
+		}
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "T+00:00:00"},
+		{90 * time.Second, "T+00:01:30"},
+		{26*time.Hour + 3*time.Minute + 4*time.Second, "T+26:03:04"},
+	}
+
+	for _, test := range tests {
+		if got := relativeTime(test.d); got != test.want {
+			t.Errorf("relativeTime(%s) = %q, want %q", test.d, got, test.want)
+		}
+	}
+}
+
+func TestTimeTicks(t *testing.T) {
+	labels := func(ticks []tick) ([]string, []int) {
+		l, o := []string{}, []int{}
+		for _, tick := range ticks {
+			l = append(l, tick.label)
+			o = append(o, tick.offset)
+		}
+		return l, o
+	}
+
+	tests := []struct {
+		name    string
+		table   *TableComplex
+		count   int
+		mode    string
+		labels  []string
+		offsets []int
+	}{
+		{
+			name:    "absolute",
+			table:   minuteTable(time.Date(2016, 3, 1, 23, 0, 0, 0, time.UTC), time.Minute, 60),
+			count:   6,
+			mode:    TimeLabelsAbsolute,
+			labels:  []string{"2016-03-01 23:00", "23:10", "23:20", "23:30", "23:40", "23:50"},
+			offsets: []int{0, 10, 20, 30, 40, 50},
+		},
+		{
+			name:    "date at the rollover",
+			table:   minuteTable(time.Date(2016, 3, 1, 23, 48, 0, 0, time.UTC), time.Minute, 23),
+			count:   4,
+			mode:    TimeLabelsAbsolute,
+			labels:  []string{"2016-03-01 23:50", "23:55", "2016-03-02 00:00", "00:05", "00:10"},
+			offsets: []int{2, 7, 12, 17, 22},
+		},
+		{
+			name:    "seconds",
+			table:   minuteTable(time.Date(2016, 3, 1, 12, 0, 5, 0, time.UTC), 10*time.Second, 10),
+			count:   3,
+			mode:    TimeLabelsAbsolute,
+			labels:  []string{"2016-03-01 12:00:30", "12:01:00", "12:01:30"},
+			offsets: []int{3, 6, 9},
+		},
+		{
+			name:    "relative",
+			table:   minuteTable(time.Date(2016, 3, 1, 23, 3, 0, 0, time.UTC), time.Minute, 31),
+			count:   3,
+			mode:    TimeLabelsRelative,
+			labels:  []string{"T+00:00:00", "T+00:10:00", "T+00:20:00", "T+00:30:00"},
+			offsets: []int{0, 10, 20, 30},
+		},
+	}
+
+	for _, test := range tests {
+		gotLabels, gotOffsets := labels(timeTicks(test.table, test.count, test.mode))
+		if !reflect.DeepEqual(gotLabels, test.labels) || !reflect.DeepEqual(gotOffsets, test.offsets) {
+			t.Errorf("%s: ticks %v at %v, want %v at %v", test.name, gotLabels, gotOffsets, test.labels, test.offsets)
+		}
+	}
+
+	if ticks := timeTicks(&TableComplex{}, 5, TimeLabelsAbsolute); len(ticks) != 0 {
+		t.Errorf("%d ticks for a table without times", len(ticks))
+	}
+}
@@ -0,0 +1,255 @@
+package gopow
+
+import (
+	"encoding/csv"
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codegangsta/cli"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	TraceMax  = "max"  // strongest bin of the range
+	TraceMean = "mean" // mean power of the bins in the range
+)
+
+// Trace is the power history of a frequency or a range of frequencies, one
+// value per row
+type Trace struct {
+	Label  string
+	HzLow  float64
+	HzHigh float64
+	Values []float64 // dB, NaN for rows without data
+}
+
+// ParseTrace reads a frequency such as 89.5M, or a range such as
+// 92.2M-92.4M
+func ParseTrace(str string) (*Trace, error) {
+	str = strings.TrimSpace(str)
+	parts := strings.SplitN(str, "-", 2)
+
+	low, err := ParseHz(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	high := low
+	if len(parts) == 2 {
+		high, err = ParseHz(parts[1])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if high < low {
+		return nil, fmt.Errorf("invalid frequency range: %s", str)
+	}
+
+	return &Trace{
+		Label:  str,
+		HzLow:  low,
+		HzHigh: high,
+	}, nil
+}
+
+// Extract aggregates the bins of the trace range in every row of the table
+// by TraceMax or TraceMean. A single frequency is the bin containing it.
+func (tr *Trace) Extract(t *TableComplex, aggregate string) error {
+	low, high := t.HzBin(tr.HzLow), t.HzBin(tr.HzHigh)
+	if high >= t.Bins && tr.HzHigh == t.HzHigh {
+		high = t.Bins - 1
+	}
+	if low < 0 || high >= t.Bins {
+		return fmt.Errorf("trace %s outside of the table", tr.Label)
+	}
+
+	tr.Values = make([]float64, t.rowCount())
+	for y := range tr.Values {
+		samples := t.Rows[y].Samples
+
+		max, linear, n := math.Inf(-1), 0.0, 0
+		for x := low; x <= high && x < len(samples); x++ {
+			if math.IsNaN(samples[x]) {
+				continue
+			}
+			max = math.Max(max, samples[x])
+			linear += math.Pow(10, samples[x]/10)
+			n++
+		}
+
+		switch {
+		case n == 0:
+			tr.Values[y] = math.NaN()
+		case aggregate == TraceMean:
+			tr.Values[y] = 10 * math.Log10(linear/float64(n))
+		default:
+			tr.Values[y] = max
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"trace": tr.Label,
+		"bins":  high - low + 1,
+	}).Debug("trace")
+
+	return nil
+}
+
+// WriteTraces writes the traces as CSV with a time column and a column per
+// trace, to file or stdout when file is empty
+func WriteTraces(t *TableComplex, traces []*Trace, file string) (err error) {
+	w, err := openOutput(file)
+	if err != nil {
+		return err
+	}
+	defer closeOutput(w, &err)
+
+	if file != "" {
+		log.WithFields(log.Fields{
+			"file":   file,
+			"traces": len(traces),
+		}).Info("writing traces")
+	}
+
+	header := []string{"time"}
+	for _, tr := range traces {
+		header = append(header, tr.Label)
+	}
+
+	writer := csv.NewWriter(w)
+	err = writer.Write(header)
+	if err != nil {
+		return err
+	}
+
+	for y := 0; y < t.rowCount(); y++ {
+		record := []string{t.Rows[y].Time.Format(time.RFC3339)}
+		for _, tr := range traces {
+			v := ""
+			if !math.IsNaN(tr.Values[y]) {
+				v = strconv.FormatFloat(tr.Values[y], 'f', 2, 64)
+			}
+			record = append(record, v)
+		}
+
+		err = writer.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// TracePlot draws the traces against time in an image of the given size,
+// the palette picks the auto theme
+func TracePlot(t *TableComplex, traces []*Trace, width, height int, palette Palette, conf AnnotatorConfig) (*image.RGBA, error) {
+	size := conf.Font.SizeForArea(width, height)
+	measure, err := conf.Font.Measurer(size)
+	if err != nil {
+		return nil, err
+	}
+	conf.Font.Size = size
+
+	layout := NewPlotLayout(width, height, Margins{
+		Title:    textMargin(size, 1),
+		TimeAxis: measure("-000 dB") + 10,
+		ColorBar: 10,
+		InfoBox:  textMargin(size, 1.5),
+	})
+	if layout.Data.Dx() < 2 || layout.Data.Dy() < 2 {
+		return nil, fmt.Errorf("trace plot too small: %dx%d", width, height)
+	}
+
+	theme, err := ResolveTheme(conf.Theme, palette, t)
+	if err != nil {
+		return nil, err
+	}
+
+	img := layout.Image(theme.Background)
+
+	annotator, err := NewAnnotator(img, t, palette, layout, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	annotator.DrawTraces(traces)
+
+	return img, nil
+}
+
+// RunTrace loads the input table and writes the traces of the selected
+// frequencies as CSV, and optionally as a plot
+func RunTrace(c *cli.Context) error {
+	input, output, plot := c.String("input"), c.String("output"), c.String("plot")
+	if input == "" {
+		return fmt.Errorf("no input file")
+	}
+
+	aggregate := c.String("aggregate")
+	switch aggregate {
+	case TraceMax, TraceMean:
+	default:
+		return fmt.Errorf("unsupported aggregate: %s", aggregate)
+	}
+
+	traces := []*Trace{}
+	for _, str := range splitList(c.String("freq")) {
+		tr, err := ParseTrace(str)
+		if err != nil {
+			return err
+		}
+		traces = append(traces, tr)
+	}
+	if len(traces) == 0 {
+		return fmt.Errorf("no frequencies to trace")
+	}
+
+	conf, err := commandRenderConfig(c)
+	if err != nil {
+		return err
+	}
+
+	chartConfig, err := commandChartConfig(c, "trace")
+	if err != nil {
+		return err
+	}
+
+	table, err := NewTable(input, conf)
+	if err != nil {
+		return err
+	}
+
+	for _, tr := range traces {
+		err = tr.Extract(table, aggregate)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = WriteTraces(table, traces, output)
+	if err != nil {
+		return err
+	}
+
+	if plot == "" {
+		return nil
+	}
+
+	img, err := TracePlot(table, traces, c.Int("width"), c.Int("height"), newPalette(chartConfig.Palette), chartConfig.Annotation)
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"file": plot,
+	}).Info("writing trace plot")
+
+	return writeImage(img, plot, imageFormat(plot))
+}
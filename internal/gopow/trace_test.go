@@ -0,0 +1,78 @@
+package gopow
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseTrace(t *testing.T) {
+	tests := []struct {
+		str       string
+		low, high float64
+	}{
+		{"89.5M", 89.5e6, 89.5e6},
+		{"92.2M-92.4M", 92.2e6, 92.4e6},
+		{" 446.00625MHz ", 446.00625e6, 446.00625e6},
+	}
+
+	for _, test := range tests {
+		tr, err := ParseTrace(test.str)
+		if err != nil {
+			t.Errorf("ParseTrace(%q): %s", test.str, err)
+			continue
+		}
+		if math.Abs(tr.HzLow-test.low) > 1e-3 || math.Abs(tr.HzHigh-test.high) > 1e-3 {
+			t.Errorf("ParseTrace(%q) = %g-%g, want %g-%g", test.str, tr.HzLow, tr.HzHigh, test.low, test.high)
+		}
+	}
+
+	for _, str := range []string{"", "fm", "92.4M-92.2M", "92.2M-x"} {
+		if _, err := ParseTrace(str); err == nil {
+			t.Errorf("ParseTrace(%q) without an error", str)
+		}
+	}
+}
+
+func TestTraceExtract(t *testing.T) {
+	nan := math.NaN()
+	table := gridTable([][]float64{
+		{-40, -30, -20, nan},
+		{-40, nan, nan, nan},
+		{nan, nan, nan, nan},
+	})
+
+	tests := []struct {
+		trace     string
+		aggregate string
+		want      []float64
+	}{
+		{"88.15M", TraceMax, []float64{-30, nan, nan}},
+		{"88.05M-88.25M", TraceMax, []float64{-20, -40, nan}},
+		{"88.05M-88.25M", TraceMean, []float64{10 * math.Log10((1e-4+1e-3+1e-2)/3), -40, nan}},
+		{"88.3M-88.4M", TraceMax, []float64{nan, nan, nan}},
+	}
+
+	for _, test := range tests {
+		tr, err := ParseTrace(test.trace)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tr.Extract(table, test.aggregate); err != nil {
+			t.Errorf("%s: %s", test.trace, err)
+			continue
+		}
+		for y, want := range test.want {
+			got := tr.Values[y]
+			if math.IsNaN(want) != math.IsNaN(got) || (!math.IsNaN(want) && math.Abs(got-want) > 1e-9) {
+				t.Errorf("%s %s: row %d = %g, want %g", test.trace, test.aggregate, y, got, want)
+			}
+		}
+	}
+
+	for _, str := range []string{"87.9M", "88.5M", "87.9M-88.1M"} {
+		tr, _ := ParseTrace(str)
+		if err := tr.Extract(table, TraceMax); err == nil {
+			t.Errorf("%s: no error for a trace outside of the table", str)
+		}
+	}
+}
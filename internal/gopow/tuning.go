@@ -0,0 +1,111 @@
+package gopow
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/codegangsta/cli"
+	log "github.com/sirupsen/logrus"
+)
+
+// TuningConfig maps the tuned frequencies of the dongle to the true RF
+// frequencies, for frequency converters and dongles with a known error
+type TuningConfig struct {
+	Offset float64 // Hz added to the tuned frequency, -125M for a 125 MHz upconverter
+	Invert bool    // the converter mixes with an LO above the RF, the offset minus the tuned frequency
+	PPM    float64 // frequency error of the dongle, positive when it tunes high
+}
+
+// NewTuningConfig reads the tuning flags, nil when they leave the
+// frequencies as tuned
+func NewTuningConfig(c *cli.Context) (*TuningConfig, error) {
+	config := &TuningConfig{
+		Invert: c.Bool("freq-invert"),
+		PPM:    c.Float64("ppm"),
+	}
+
+	if str := c.String("freq-offset"); str != "" {
+		offset, err := ParseHz(str)
+		if err != nil {
+			return nil, err
+		}
+		config.Offset = offset
+	}
+
+	if config.PPM <= -1e6 {
+		return nil, fmt.Errorf("invalid ppm: %g", config.PPM)
+	}
+
+	if config.Offset == 0 && !config.Invert && config.PPM == 0 {
+		return nil, nil
+	}
+
+	return config, nil
+}
+
+// Hz returns the RF frequency of a tuned frequency
+func (tc *TuningConfig) Hz(tuned float64) float64 {
+	hz := tuned * (1 + tc.PPM/1e6)
+	if tc.Invert {
+		return tc.Offset - hz
+	}
+	return hz + tc.Offset
+}
+
+// Retune moves the table to the RF frequencies of the tuning. An inverted
+// spectrum has its bins and hops reversed so that frequencies still grow
+// with the bin, rows cut short by a partial sweep are padded with missing
+// samples to mirror onto the same bins as the full rows.
+func (t *TableComplex) Retune(tc *TuningConfig) error {
+	low, high := tc.Hz(t.HzLow), tc.Hz(t.HzHigh)
+	if tc.Invert {
+		low, high = high, low
+	}
+	if low < 0 {
+		return fmt.Errorf("tuning moves the band below 0 Hz: %g", low)
+	}
+
+	log.WithFields(log.Fields{
+		"tuned": fmt.Sprintf("%s-%s", humanHz(t.HzLow), humanHz(t.HzHigh)),
+		"rf":    fmt.Sprintf("%s-%s", humanHz(low), humanHz(high)),
+	}).Info("retuned")
+
+	t.HzLow, t.HzHigh = low, high
+
+	for _, row := range t.Rows {
+		row.HzLow, row.HzHigh = tc.Hz(row.HzLow), tc.Hz(row.HzHigh)
+		row.HzStep *= 1 + tc.PPM/1e6
+
+		if !tc.Invert {
+			continue
+		}
+
+		row.HzLow, row.HzHigh = row.HzHigh, row.HzLow
+		for len(row.Samples) > 0 && len(row.Samples) < t.Bins {
+			row.Samples = append(row.Samples, math.NaN())
+		}
+		for i, j := 0, len(row.Samples)-1; i < j; i, j = i+1, j-1 {
+			row.Samples[i], row.Samples[j] = row.Samples[j], row.Samples[i]
+		}
+		row.Hops = reverseHops(row.Hops, len(row.Samples))
+	}
+
+	if tc.Invert {
+		t.Hops = reverseHops(t.Hops, t.Bins)
+	}
+
+	return nil
+}
+
+// reverseHops returns the first bin of every hop of reversed samples
+func reverseHops(hops []int, bins int) []int {
+	reversed := make([]int, len(hops))
+	for i := range hops {
+		end := bins
+		if i+1 < len(hops) {
+			end = hops[i+1]
+		}
+		reversed[len(hops)-1-i] = bins - end
+	}
+	return reversed
+}
@@ -0,0 +1,83 @@
+package gopow
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTuningHz(t *testing.T) {
+	tests := []struct {
+		tc    TuningConfig
+		tuned float64
+		want  float64
+	}{
+		{TuningConfig{}, 100e6, 100e6},
+		{TuningConfig{Offset: -125e6}, 135e6, 10e6},
+		{TuningConfig{Offset: 200e6, Invert: true}, 91.3e6, 108.7e6},
+		{TuningConfig{PPM: 50}, 100e6, 100.005e6},
+	}
+
+	for _, test := range tests {
+		if got := test.tc.Hz(test.tuned); math.Abs(got-test.want) > 1e-3 {
+			t.Errorf("%+v: Hz(%g) = %g, want %g", test.tc, test.tuned, got, test.want)
+		}
+	}
+}
+
+func TestReverseHops(t *testing.T) {
+	tests := []struct {
+		hops []int
+		bins int
+		want []int
+	}{
+		{[]int{0}, 8, []int{0}},
+		{[]int{0, 4}, 8, []int{0, 4}},
+		{[]int{0, 3}, 8, []int{0, 5}},
+		{[]int{0, 2, 5}, 10, []int{0, 5, 8}},
+	}
+
+	for _, test := range tests {
+		if got := reverseHops(test.hops, test.bins); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("reverseHops(%v, %d) = %v, want %v", test.hops, test.bins, got, test.want)
+		}
+	}
+}
+
+func TestRetuneInvertShortRow(t *testing.T) {
+	table := &TableComplex{Config: &RenderConfig{}}
+	table.Rows = table.parseBuffer([]byte(sweepCSV(2, 1)))
+
+	full, short := table.Rows[0], table.Rows[1]
+	if len(full.Samples) != 8 || len(short.Samples) != 4 {
+		t.Fatalf("rows of %d and %d samples, want 8 and 4", len(full.Samples), len(short.Samples))
+	}
+
+	err := table.Retune(&TuningConfig{Offset: 200e6, Invert: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if table.HzLow != 108e6 || table.HzHigh != 112e6 {
+		t.Fatalf("span %g-%g, want 108e6-112e6", table.HzLow, table.HzHigh)
+	}
+
+	// the tuned bin x is the RF bin 7-x in every row
+	for x, want := range []float64{-40.0, -41.0, -42.0, -43.0} {
+		if got := full.Samples[7-x]; got != want {
+			t.Errorf("full row bin %d = %g, want %g", 7-x, got, want)
+		}
+		if got := short.Samples[7-x]; got != want {
+			t.Errorf("short row bin %d = %g, want %g", 7-x, got, want)
+		}
+	}
+	for x := 0; x < 4; x++ {
+		if !math.IsNaN(short.Samples[x]) {
+			t.Errorf("short row bin %d = %g, want missing", x, short.Samples[x])
+		}
+	}
+
+	if !reflect.DeepEqual(table.Hops, []int{0, 4}) || !reflect.DeepEqual(short.Hops, []int{0}) {
+		t.Errorf("hops %v and short row hops %v", table.Hops, short.Hops)
+	}
+}
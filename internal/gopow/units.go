@@ -0,0 +1,36 @@
+package gopow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseHz reads a frequency in Hz, optionally with an SI suffix such as
+// 89.5M, 446.00625MHz or 2.4G
+func ParseHz(str string) (float64, error) {
+	str = strings.TrimSpace(str)
+	num := strings.TrimSuffix(strings.TrimSuffix(str, "Hz"), "hz")
+
+	scale := 1.0
+	if len(num) > 0 {
+		switch num[len(num)-1] {
+		case 'k', 'K':
+			scale = 1e3
+		case 'M':
+			scale = 1e6
+		case 'G', 'g':
+			scale = 1e9
+		}
+		if scale != 1 {
+			num = num[:len(num)-1]
+		}
+	}
+
+	hz, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid frequency: %s", str)
+	}
+
+	return hz * scale, nil
+}
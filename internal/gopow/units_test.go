@@ -0,0 +1,40 @@
+package gopow
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseHz(t *testing.T) {
+	tests := []struct {
+		str  string
+		want float64
+	}{
+		{"100", 100},
+		{"12.5k", 12.5e3},
+		{"12.5K", 12.5e3},
+		{"89.5M", 89.5e6},
+		{"446.00625MHz", 446.00625e6},
+		{"2.4G", 2.4e9},
+		{"2.4ghz", 2.4e9},
+		{" 10 M ", 10e6},
+		{"50Hz", 50},
+	}
+
+	for _, test := range tests {
+		got, err := ParseHz(test.str)
+		if err != nil {
+			t.Errorf("ParseHz(%q): %s", test.str, err)
+			continue
+		}
+		if math.Abs(got-test.want) > 1e-3 {
+			t.Errorf("ParseHz(%q) = %g, want %g", test.str, got, test.want)
+		}
+	}
+
+	for _, str := range []string{"", "M", "low", "5m", "89.5 MHz FM"} {
+		if got, err := ParseHz(str); err == nil {
+			t.Errorf("ParseHz(%q) = %g, want an error", str, got)
+		}
+	}
+}
@@ -25,6 +25,141 @@ func bindata_read(data []byte, name string) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+var _resources_bandplans_aviation_csv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x44\xce\xc1\x4e\x02\x31\x10\xc6\xf1\x7b\x9f\xa2\xc9\x5e\x47\x99\xd9\xda\x2d\x73\x2c\xae\x07\x13\x07\x13\x41\xef\x65\x61\xa1\xa1\xb4\x49\xb3\x68\x7c\x7b\xb3\x1e\xe0\xfa\xfd\x92\x2f\xff\x46\xfb\x43\x2d\x39\x5c\xa7\x38\x84\xa4\x73\xf8\x8e\xc7\x30\xc5\x92\x75\xc8\x7b\x3d\x94\xcb\xe5\x9a\xe3\xf0\xbf\xa8\x54\x7e\xe0\x14\x8f\x27\x48\x61\x77\x48\x30\x94\x54\xaa\x22\xc6\x33\x58\x63\xcf\xb0\xee\x57\xd0\x8c\x23\xb3\x31\x8a\x70\x29\x40\xe4\x1e\xd9\x59\x81\xd7\xb7\xcd\xe2\xeb\xfd\xe3\xae\x34\xab\x71\x02\x3e\xd6\x5d\xc8\xfb\x59\xba\x0e\x51\xb5\xad\x15\x78\x42\x14\x90\x98\xe2\x14\xea\xaf\x0e\xb1\xde\x98\x3b\x14\xa0\x96\xac\x40\x2f\x2f\x8b\xad\x7f\xf6\xeb\xdb\x2b\x3b\x27\xc0\x8e\x05\x3e\xfd\x56\xb3\x5b\xce\x62\x0c\xe2\x5c\xc3\x02\x84\x4c\x02\xbe\xdf\x3c\xac\x34\x21\x23\x34\xe3\x68\x0c\xa2\xfa\x1b\x00\x3c\x16\xaa\xda\x04\x01\x00\x00")
+
+func resources_bandplans_aviation_csv() ([]byte, error) {
+	return bindata_read(
+		_resources_bandplans_aviation_csv,
+		"resources/bandplans/aviation.csv",
+	)
+}
+
+var _resources_bandplans_broadcast_csv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x8d\x31\x6f\xc2\x30\x10\x46\x77\xff\x0a\x4b\x59\xaf\xd4\xf6\x39\x17\x67\x2c\xaa\x50\x91\xb8\xad\x4d\x67\xe7\x92\x94\xa8\x16\x96\x02\x6a\xff\x7e\x65\x28\x82\x81\xe9\x86\xef\xbd\x77\x95\x5e\x2f\x39\x0e\x12\x8f\x27\xbd\xc4\x61\xce\x3a\x1e\x06\x7d\x1a\xd3\xf8\x33\x1f\xe7\x7c\x50\x29\xff\xc2\x7e\xfe\xda\x43\x8a\xfd\x98\x40\x72\xca\x8b\xb2\x3e\xac\xea\x6f\x70\x01\xcb\xd9\x7d\xea\xfe\x1a\x81\x0a\xb1\x6d\xa7\x49\xd5\x8e\xca\x66\xc9\x9c\x2f\x3f\x62\x7c\xc3\x40\x81\xe1\xbd\xd3\x7d\x79\xbb\x85\x8a\x88\x68\x9a\x54\x68\x56\x35\x83\x35\x81\x61\xc3\x0f\x4c\xdb\x78\x06\x87\xe6\xce\xdd\xde\xec\xcb\xea\x0d\xc3\xeb\xcb\xfa\x6e\x46\x14\x11\x51\xbe\x31\x0c\x81\xdc\x59\xfe\x78\xdb\xfc\x13\xdd\x73\x77\x2b\xf8\xda\x31\x58\xdf\xba\x4b\x63\xf7\x54\x18\xa8\x10\x45\x44\xd4\xdf\x00\xd8\x58\xec\x4e\x36\x01\x00\x00")
+
+func resources_bandplans_broadcast_csv() ([]byte, error) {
+	return bindata_read(
+		_resources_bandplans_broadcast_csv,
+		"resources/bandplans/broadcast.csv",
+	)
+}
+
+var _resources_bandplans_ism_csv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4c\x8f\xc1\x6a\xc3\x30\x0c\x86\xef\x79\x0a\x43\xae\x42\xb8\xb2\x25\xd9\xf7\x41\xe9\x41\x97\xe5\x09\x52\x27\x34\x85\x90\x40\x3a\xb6\xd7\x1f\x59\x82\xd7\x93\x84\xbe\xef\xe7\x47\xad\xbb\x75\xe6\xfa\x65\x70\xaf\x69\xdd\xbe\xdc\xd6\x2f\x8f\xd1\x0d\xe3\xf7\xb3\x8c\xee\xde\x2f\xc3\xab\x99\xd7\x1f\x98\x9e\x8f\x09\xe6\xfe\x3e\xce\x50\xd6\x79\xdd\x1a\x41\x15\x36\x10\xd4\xcc\x06\xb7\xce\x9c\xa0\x26\x68\x45\x4a\x11\x69\x2e\x01\x99\x83\xc1\x3e\x45\x0f\xe1\x6f\xaf\x06\x09\x66\x56\x03\x52\xa4\x14\x0e\x83\xb4\xe2\xe8\x51\xc4\x20\x7a\x54\x7f\xc0\xfd\xf2\x5f\x10\x43\x40\xcf\x06\x31\x44\xd4\x7c\x1a\x21\x54\x9e\x24\x18\xa4\x3d\xdb\x7d\x7e\xb8\xf4\x96\xcc\x9e\x0c\x32\xa5\x23\x93\x2f\x5c\x09\x45\xef\x0d\x88\xfd\xd9\x48\x18\xaf\x15\xb2\x12\x1b\x70\xd2\xf3\x5d\xc6\x74\x85\x56\xa4\x14\x91\xe6\x77\x00\x0d\x62\x5a\xb2\x46\x01\x00\x00")
+
+func resources_bandplans_ism_csv() ([]byte, error) {
+	return bindata_read(
+		_resources_bandplans_ism_csv,
+		"resources/bandplans/ism.csv",
+	)
+}
+
+var _resources_bandplans_itu_r1_amateur_csv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4c\x90\xc1\x8a\xe3\x30\x0c\x40\xef\xfd\x0a\x43\x2f\xbb\x20\x84\x65\x4b\xb6\x73\xdc\x43\x29\x7b\xf0\x6d\xf7\x03\xdc\x34\xd3\x86\xa4\xf5\x90\xb6\xcc\xef\x0f\xce\x14\xa2\x8b\x0d\xef\xe5\x09\x2b\x7b\xf3\xf7\xdf\x7f\xb3\x0c\x97\xb1\xde\x0d\x99\x72\x2b\xcf\xe1\xb5\x98\xa5\x9c\xc7\x6a\xca\x3c\xd7\xbe\x3c\xc7\x7a\x7f\x98\x5f\x87\xd7\x52\x3f\x07\x30\x7f\x3e\x96\xb1\x2f\x60\xf2\x78\x3e\xcf\x83\x39\x94\xc7\xf3\xf7\x6e\xae\x5f\x70\x1d\x2f\x57\x98\xcb\x69\x98\xa1\xaf\x73\x5d\x76\xe4\x05\xe3\x04\xe4\x23\xa6\x09\x9c\xb3\xf6\x06\xfb\x21\x9c\x92\xb5\x3b\x8e\x6e\x02\x8e\xdd\x04\xc1\x2b\x4c\x98\x28\x83\x43\x9b\x81\x82\xe2\x1e\x25\x83\xc7\x94\x21\x29\x2a\xe8\x85\x24\x83\xa0\x0f\x41\x32\xe8\x22\xb6\x19\x11\x5d\x06\x56\x94\x2c\x52\x86\x76\xb6\x79\x5a\x70\xfb\x9e\x18\xbd\x64\x70\x5a\x24\xb4\x21\x65\xa0\x84\xb4\xde\x71\x73\x8e\x5a\xe4\x08\x59\x32\x90\x28\xc1\x98\xba\x0c\x8e\xb1\xeb\x32\x90\x53\x26\xad\x49\x87\xb1\x3d\x63\xe3\x62\x1b\x97\x75\xf3\xb0\xe1\xb8\xe2\x68\xdb\xfa\xbc\x61\xe2\xf7\x73\x43\xbb\xd4\x78\xf6\x6b\xc0\xfc\xee\x7a\xd5\x38\x6e\x89\xb7\xad\xf0\x4a\xb8\x1f\xc4\xb2\x6a\x25\x24\x34\x24\xa9\x9d\x41\x71\xb2\x68\x8f\xed\x27\xca\x11\xf4\xa0\xef\x01\x00\xca\x9c\x12\xea\x4c\x02\x00\x00")
+
+func resources_bandplans_itu_r1_amateur_csv() ([]byte, error) {
+	return bindata_read(
+		_resources_bandplans_itu_r1_amateur_csv,
+		"resources/bandplans/itu-r1-amateur.csv",
+	)
+}
+
+var _resources_bandplans_itu_r2_amateur_csv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\x90\xcd\x8a\xeb\x30\x0c\x85\xf7\x7d\x0a\x43\x37\xf7\x82\x10\x96\x6c\xf9\x67\x79\x57\xe5\x2e\xbc\x9b\x79\x80\x34\x13\xda\x90\xb4\x86\xb4\xc3\xbc\xfe\x60\x77\x20\x9a\x4d\x0c\xdf\x97\x73\x24\x74\x34\xff\xdf\xde\xcd\x36\x5d\xe6\x7a\x37\x6c\x86\xdb\xf0\x9c\x3e\x37\xb3\x0d\x1f\x73\x35\xc3\xba\xd6\x71\x78\xce\xf5\xfe\x30\x7f\x9e\xd7\xc9\xfc\xbb\x4d\xdb\x3c\x0e\x8f\xbf\x87\xb5\x7e\xc1\x75\xbe\x5c\x61\x1d\xce\xd3\x0a\x63\x5d\xeb\x76\x20\x27\x18\x17\x20\x17\x31\x2d\xc0\x6c\xed\x0d\x8e\x53\x38\x27\x6b\x0f\x3e\xf2\x02\x3e\xe6\x05\x82\x53\x98\x30\x15\x60\xb4\x05\x28\x28\xec\x50\x0a\xf8\x86\x93\xa2\x82\x4e\x48\x0a\x08\xba\x10\xa4\x80\x4e\xc4\xf6\x73\x44\x57\xc0\x2b\x4a\x16\xa9\x40\xfb\x4a\x81\x5f\x73\x7b\x39\x79\x74\x52\x80\xb5\x48\x68\x43\x2a\x40\x09\xa9\xbf\x71\x77\x4c\x2d\xc4\x84\x5e\x0a\x90\x28\xe1\x31\xe5\x02\xec\x31\xe7\x02\xc4\xca\xa4\x1e\xc9\x18\xdb\x1a\x3b\x17\xdb\xb8\xf4\x2d\xc2\x8e\xc9\xff\xec\xf5\x8a\xed\x82\xb9\x1f\x89\x59\xda\x43\xc8\x6a\xba\xe7\x5e\xe6\x5f\x9d\xd1\x8e\xbb\xca\xb6\xc7\x32\xf7\x3e\xe7\x94\x22\xf6\xad\xc9\xd9\x56\xab\x05\xbf\x90\x97\xae\x95\x90\xd0\x90\x64\x6e\xa7\x57\x9c\x2c\xda\x53\x3b\xb2\x9c\x40\x17\x7d\x0f\x00\x42\x18\x70\x20\x5c\x02\x00\x00")
+
+func resources_bandplans_itu_r2_amateur_csv() ([]byte, error) {
+	return bindata_read(
+		_resources_bandplans_itu_r2_amateur_csv,
+		"resources/bandplans/itu-r2-amateur.csv",
+	)
+}
+
+var _resources_bandplans_itu_r3_amateur_csv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4c\x90\xc1\x8a\xe3\x30\x0c\x40\xef\xfd\x0a\x43\x2f\xbb\x20\x84\x65\x4b\xb6\x73\xdc\x53\xd9\x83\x61\x0f\x3b\x1f\xe0\x66\x32\xad\x49\x5a\x43\xda\x61\x7e\x7f\x70\x3a\x10\x5d\x2c\x78\x2f\x4f\x88\x1c\xcd\xdf\xff\x6f\x66\x9d\x2e\xb5\xdd\x8d\x37\xe5\x56\x9e\xd3\xe7\x6a\xd6\xf2\x5e\x9b\x29\xcb\xd2\xc6\xf2\xac\xed\xfe\x30\xbf\xfe\x3c\x6a\x01\xf3\xaf\x8c\xf5\xa3\x8e\xbf\x0f\x4b\xfb\x82\x6b\xbd\x5c\x61\x29\xe7\x69\x81\xb1\x2d\x6d\x3d\x90\x17\x8c\x33\x90\x8f\x98\x66\x70\xce\xda\x1b\x1c\xa7\x70\x4e\xd6\x1e\x38\xba\x19\x38\x0e\x33\x04\xaf\x30\x61\xca\xe0\xd0\x66\xa0\xa0\xb0\x47\xc9\xe0\x71\xc8\x90\x14\x15\xf4\x42\x92\x41\xd0\x87\x20\x19\x74\x11\xfb\x8e\x88\x3e\x03\x2b\x4a\x16\x29\x43\x7f\xfb\x3e\x2d\xb8\x7f\x4f\x8c\x5e\x32\x38\x2d\x12\xda\x90\x32\x50\x42\xda\x66\xdc\x9d\xa3\x1e\x39\x42\x96\x0c\x24\x4a\x30\xa6\x21\x83\x63\x1c\x86\x0c\xe4\x94\x49\x5b\x32\x60\xec\x67\xec\x5c\x6c\xe7\xb2\x5d\x11\x76\x4c\xfc\x73\xd7\x2b\xdb\x05\xfb\x2d\x60\xde\x46\xb4\xa3\x6a\x1c\xf7\xc4\xdb\x5e\x78\x25\xdc\x0b\xb1\x6c\x5a\x09\x09\x1d\x49\xea\x6f\x50\x9c\x2c\xda\x53\xff\x5b\x72\x02\xbd\xe8\x7b\x00\x4b\x10\xfa\xd3\x26\x02\x00\x00")
+
+func resources_bandplans_itu_r3_amateur_csv() ([]byte, error) {
+	return bindata_read(
+		_resources_bandplans_itu_r3_amateur_csv,
+		"resources/bandplans/itu-r3-amateur.csv",
+	)
+}
+
+var _resources_bandplans_marine_csv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x34\xcc\x31\x0b\xc2\x30\x10\x05\xe0\x3d\xbf\x22\xd0\xf5\x08\x77\x91\xa4\x66\xec\xa0\xe8\x10\x17\xa5\xb8\xd6\x90\xb3\xa1\xd1\x42\x2a\xfa\xf7\x25\x15\xb7\x07\xef\x7b\xaf\x91\x7e\x28\xe9\x95\x1e\x51\x2e\xb1\xbc\x53\x88\x8b\xc8\xf3\x07\xc6\x74\x1f\x21\x0f\xb7\x98\x21\xcc\x79\x2e\xc2\x50\x3b\x81\x21\x37\xc1\xa9\xeb\x2f\xbb\x2b\x34\x88\x21\x84\x20\xc8\x58\x85\x1e\xc8\x6a\x85\xda\x78\xa8\x7f\xcf\x28\xfb\xc3\xbe\x12\xe7\x9c\x13\x64\x49\x39\x5b\xcb\x35\x6d\x5b\xe3\xa1\x3b\x9e\x25\x55\xc1\xcc\x2c\xd6\x35\xfd\x84\x56\xb8\xf9\x0b\x0d\x0d\x22\x33\xb3\xf8\x0e\x00\x8c\xea\x25\xe1\xa9\x00\x00\x00")
+
+func resources_bandplans_marine_csv() ([]byte, error) {
+	return bindata_read(
+		_resources_bandplans_marine_csv,
+		"resources/bandplans/marine.csv",
+	)
+}
+
+var _resources_bandplans_pmr_csv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\xcc\xc1\x4a\xc4\x30\x10\xc6\xf1\x7b\x9f\x22\xd0\xeb\x30\x36\xc9\x64\x62\xaf\x2a\x7a\xd9\x81\xa5\xc5\x07\xa8\xd9\x89\x5b\x08\x8d\xa4\xa0\xaf\x2f\x45\x54\xf6\xf6\xc1\xef\xe3\xdf\x9b\xd3\x9a\x74\x4b\x6a\x72\x53\x35\x1f\xda\xf6\xba\x2d\xc5\xb4\xe5\xb2\x56\xb3\x6b\xfb\x5c\x93\xee\x5d\xa9\x5f\x70\x5d\xdf\xaf\x50\x96\x37\x2d\x90\x6a\xa9\xad\x73\x8c\x23\x07\x01\x17\x91\x86\x20\xf0\xf8\x60\x5c\x84\x3e\x25\xe6\x9c\x3b\x1b\x2c\xde\x3b\x01\x1b\x08\x59\x40\x5e\xa7\xf9\xcf\xc8\x7b\x1c\x62\x10\x20\x4f\x18\x8f\x71\x3a\x3f\x91\xf7\xff\x07\x62\x1c\x04\x88\x18\xad\xc0\x59\x26\x22\xbe\x41\xfb\x83\x4e\xe0\xf2\xab\xe3\xe8\xfd\xa1\xec\x30\x1c\x69\x8e\x18\x5d\x10\x78\x9e\xe6\xbb\x17\x99\x66\xe8\x53\x62\xce\xb9\xfb\x1e\x00\x6c\xec\x09\xc1\xf4\x00\x00\x00")
+
+func resources_bandplans_pmr_csv() ([]byte, error) {
+	return bindata_read(
+		_resources_bandplans_pmr_csv,
+		"resources/bandplans/pmr.csv",
+	)
+}
+
+var _resources_channels_dab_csv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x34\x91\x41\x6e\xe3\x30\x0c\x45\xf7\x3e\x85\x81\xd9\x0a\x04\x49\xc9\xa2\xb8\x4c\x26\x33\x40\x16\xe9\x1d\x12\xd7\x29\x82\x16\x4e\x6b\x3b\xf7\x2f\x44\xd1\x4b\x22\x78\xef\x3f\xc5\x7f\xfa\xd3\xe1\xd8\xdf\xae\xf3\x7b\x7f\x3e\x9f\xfb\xdb\xd7\x73\xfc\x5c\x43\x3f\x4e\xf3\x36\x2d\xfd\x7d\x99\x7e\x5e\xd3\x3c\x3e\xa6\xb5\x5b\xb7\xeb\xb2\x85\xf5\xfb\x3a\x3e\xe6\x8f\x70\x7f\x2c\xeb\x16\xc6\xe7\x6b\xde\x3a\x92\x04\xca\xe5\x12\xc2\x70\x08\xd4\x91\x64\xc8\x09\xeb\x79\xb4\xb3\x40\x1c\xb8\x9e\x7f\xeb\x59\x10\x30\xa7\x7a\x9e\xec\x24\xd0\x98\x2f\x21\x64\x63\x4b\x84\x9c\xaa\x2a\x1b\x5b\x06\x88\xb9\xaa\x72\x63\x05\x50\xaa\x2a\x37\xb6\xf8\xae\x18\xab\xe8\xbb\x62\xac\xb2\xef\x8a\xb1\x9a\x7c\x57\x8c\xd5\xc1\x77\x4b\x63\xc5\x77\x4b\x63\xd5\x77\x4b\x65\x19\xc9\x77\x4b\x65\x19\xd9\x77\xb5\xb2\x8c\xc9\x77\xb5\xb2\x8c\xd9\x77\xb5\xb1\xc5\x77\xb5\xb1\xea\xbb\x84\x06\x13\x02\x6a\x7d\x3f\xe1\x9b\xdd\xe4\x21\x84\x66\xa3\xe8\x25\x84\xa6\xa3\xc1\x53\x08\xcd\x47\xd9\x5b\x88\x9a\x4f\x00\x4b\xfd\x03\x89\x9a\xaf\x78\x1c\x91\xf9\x18\xbd\x8e\xc8\x7c\xcc\x9e\x47\x64\x3e\x8e\x7b\x1f\x9b\x8f\xd3\xde\xc7\xe6\xe3\x61\xef\xe3\xe6\x93\xbd\x8f\x9b\x4f\xf7\x3e\x36\x5f\x44\x90\x52\x3f\x37\x45\xf3\x45\x86\xd4\x7c\xd1\xf8\x98\x80\xd1\x7a\xa3\xf1\x71\x00\x91\xf6\x7b\xe3\x05\x52\x7b\x4f\xfc\x67\xb7\x02\x23\x5e\x42\xa0\xf8\x3f\x50\xf7\x3b\x00\xd0\xfe\x8c\xe1\xc0\x02\x00\x00")
+
+func resources_channels_dab_csv() ([]byte, error) {
+	return bindata_read(
+		_resources_channels_dab_csv,
+		"resources/channels/dab.csv",
+	)
+}
+
+var _resources_channels_fm_us_csv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x51\x00\xae\xff\x23\x20\x46\x43\x43\x20\x46\x4d\x20\x62\x72\x6f\x61\x64\x63\x61\x73\x74\x20\x63\x68\x61\x6e\x6e\x65\x6c\x73\x20\x32\x30\x31\x2d\x33\x30\x30\x0a\x73\x74\x61\x72\x74\x2c\x73\x70\x61\x63\x69\x6e\x67\x2c\x66\x69\x72\x73\x74\x2c\x63\x6f\x75\x6e\x74\x0a\x38\x38\x2e\x31\x4d\x2c\x32\x30\x30\x6b\x2c\x32\x30\x31\x2c\x31\x30\x30\x0a\x03\x00\x68\x58\x6e\x4e\x51\x00\x00\x00")
+
+func resources_channels_fm_us_csv() ([]byte, error) {
+	return bindata_read(
+		_resources_channels_fm_us_csv,
+		"resources/channels/fm-us.csv",
+	)
+}
+
+var _resources_channels_lpd433_csv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x4d\x00\xb2\xff\x23\x20\x4c\x50\x44\x34\x33\x33\x20\x63\x68\x61\x6e\x6e\x65\x6c\x73\x2c\x20\x32\x35\x20\x6b\x48\x7a\x20\x72\x61\x73\x74\x65\x72\x0a\x73\x74\x61\x72\x74\x2c\x73\x70\x61\x63\x69\x6e\x67\x2c\x66\x69\x72\x73\x74\x2c\x63\x6f\x75\x6e\x74\x0a\x34\x33\x33\x2e\x30\x37\x35\x4d\x2c\x32\x35\x6b\x2c\x31\x2c\x36\x39\x0a\x03\x00\x96\xef\xea\x3f\x4d\x00\x00\x00")
+
+func resources_channels_lpd433_csv() ([]byte, error) {
+	return bindata_read(
+		_resources_channels_lpd433_csv,
+		"resources/channels/lpd433.csv",
+	)
+}
+
+var _resources_channels_pmr446_csv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x5a\x00\xa5\xff\x23\x20\x50\x4d\x52\x34\x34\x36\x20\x61\x6e\x61\x6c\x6f\x67\x20\x63\x68\x61\x6e\x6e\x65\x6c\x73\x2c\x20\x31\x32\x2e\x35\x20\x6b\x48\x7a\x20\x72\x61\x73\x74\x65\x72\x0a\x73\x74\x61\x72\x74\x2c\x73\x70\x61\x63\x69\x6e\x67\x2c\x66\x69\x72\x73\x74\x2c\x63\x6f\x75\x6e\x74\x0a\x34\x34\x36\x2e\x30\x30\x36\x32\x35\x4d\x2c\x31\x32\x2e\x35\x6b\x2c\x31\x2c\x31\x36\x0a\x03\x00\xa8\x33\x2a\x5e\x5a\x00\x00\x00")
+
+func resources_channels_pmr446_csv() ([]byte, error) {
+	return bindata_read(
+		_resources_channels_pmr446_csv,
+		"resources/channels/pmr446.csv",
+	)
+}
+
+var _resources_channels_tv_uhf_eu_csv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x6b\x00\x94\xff\x23\x20\x45\x75\x72\x6f\x70\x65\x61\x6e\x20\x55\x48\x46\x20\x74\x65\x6c\x65\x76\x69\x73\x69\x6f\x6e\x20\x63\x68\x61\x6e\x6e\x65\x6c\x73\x2c\x20\x38\x20\x4d\x48\x7a\x20\x77\x69\x64\x65\x2c\x20\x63\x65\x6e\x74\x65\x72\x20\x66\x72\x65\x71\x75\x65\x6e\x63\x69\x65\x73\x0a\x73\x74\x61\x72\x74\x2c\x73\x70\x61\x63\x69\x6e\x67\x2c\x66\x69\x72\x73\x74\x2c\x63\x6f\x75\x6e\x74\x0a\x34\x37\x34\x4d\x2c\x38\x4d\x2c\x32\x31\x2c\x34\x39\x0a\x03\x00\x7e\xa2\x3e\x82\x6b\x00\x00\x00")
+
+func resources_channels_tv_uhf_eu_csv() ([]byte, error) {
+	return bindata_read(
+		_resources_channels_tv_uhf_eu_csv,
+		"resources/channels/tv-uhf-eu.csv",
+	)
+}
+
+var _resources_channels_tv_uhf_us_csv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x65\x00\x9a\xff\x23\x20\x55\x53\x20\x55\x48\x46\x20\x74\x65\x6c\x65\x76\x69\x73\x69\x6f\x6e\x20\x63\x68\x61\x6e\x6e\x65\x6c\x73\x2c\x20\x36\x20\x4d\x48\x7a\x20\x77\x69\x64\x65\x2c\x20\x63\x65\x6e\x74\x65\x72\x20\x66\x72\x65\x71\x75\x65\x6e\x63\x69\x65\x73\x0a\x73\x74\x61\x72\x74\x2c\x73\x70\x61\x63\x69\x6e\x67\x2c\x66\x69\x72\x73\x74\x2c\x63\x6f\x75\x6e\x74\x0a\x34\x37\x33\x4d\x2c\x36\x4d\x2c\x31\x34\x2c\x32\x33\x0a\x03\x00\xe6\x49\xe7\x24\x65\x00\x00\x00")
+
+func resources_channels_tv_uhf_us_csv() ([]byte, error) {
+	return bindata_read(
+		_resources_channels_tv_uhf_us_csv,
+		"resources/channels/tv-uhf-us.csv",
+	)
+}
+
+var _resources_channels_wifi24_csv = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x61\x00\x9e\xff\x23\x20\x32\x2e\x34\x20\x47\x48\x7a\x20\x57\x69\x2d\x46\x69\x20\x63\x68\x61\x6e\x6e\x65\x6c\x73\x2c\x20\x63\x65\x6e\x74\x65\x72\x20\x66\x72\x65\x71\x75\x65\x6e\x63\x69\x65\x73\x0a\x73\x74\x61\x72\x74\x2c\x73\x70\x61\x63\x69\x6e\x67\x2c\x66\x69\x72\x73\x74\x2c\x63\x6f\x75\x6e\x74\x0a\x32\x34\x31\x32\x4d\x2c\x35\x4d\x2c\x31\x2c\x31\x33\x0a\x32\x34\x38\x34\x4d\x2c\x2c\x31\x34\x2c\x31\x0a\x03\x00\xb0\xbb\x90\x92\x61\x00\x00\x00")
+
+func resources_channels_wifi24_csv() ([]byte, error) {
+	return bindata_read(
+		_resources_channels_wifi24_csv,
+		"resources/channels/wifi24.csv",
+	)
+}
+
 var _resources_fonts_copying = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x55\x4d\xb3\xda\x36\x14\xdd\xeb\x57\x9c\x79\x8b\x4e\x32\xf1\xd0\xb4\xcb\xac\xe2\x07\x02\x34\x31\x32\xb5\x45\xe8\x5b\x0a\x5b\x60\xb5\xb6\xc4\x48\xf2\x23\xf4\xd7\x77\x24\x13\xf2\x5e\x3e\x76\x8c\xb8\xe7\xe3\x1e\x5f\x5d\x15\xe3\x17\x8d\xa3\x35\xc1\xa3\xb1\xe7\xab\xd3\xa7\x2e\xe0\x4d\xf3\x16\x7f\xbe\x7f\xff\x07\x0e\x57\x3c\xea\x93\xea\xed\x05\xbf\x61\x6d\xfb\x41\x79\x30\xd3\xcc\x70\x87\x81\x68\xe3\x83\x1b\x9b\xa0\xad\x41\x63\x5b\xf5\x0b\x9e\x5d\xb5\x7f\xf7\x0e\xab\xe1\xb0\x9e\x21\xef\x7b\x54\xb1\xc2\x83\x54\xca\x2b\xf7\xac\xda\x1b\xa7\xf6\x90\x70\xea\xa4\x7d\x50\x4e\xb5\x08\x4e\xb6\x6a\x90\xee\x5f\xd8\xe3\xcf\xbd\x10\xb2\x55\x6e\xd0\xde\x47\x03\xda\xa3\x53\x4e\x1d\xae\x38\x39\x69\x82\x6a\x33\x1c\x9d\x52\x11\xdc\x74\xd2\x9d\x54\x86\x60\x21\xcd\x15\x67\xe5\xbc\x35\xb0\x87\x20\xb5\xd1\xe6\x04\x22\x93\xf3\x58\x1a\x3a\xe5\x15\x96\x29\x15\x69\x5a\x48\xef\x6d\xa3\x65\x50\x2d\x5a\xdb\x8c\x83\x32\x41\xa6\x7e\x8f\xba\x57\x1e\x6f\x42\xa7\xf0\xb0\x4c\x69\xd4\xf6\x18\x2e\xd2\xa9\x87\xb7\x49\xa9\x55\xb2\x87\x36\x91\x31\xf1\xe1\xeb\xff\x19\xb4\x69\xfa\xb1\x8d\xca\x17\x1d\x3a\x3b\x06\x90\x5e\x0f\xfa\xc6\x1c\x01\x6e\x8a\x28\x58\x8c\x5e\x65\xc9\x5d\x86\x41\xa5\x2e\xce\xe3\xa1\xd7\xbe\xcb\xd0\x6a\x1f\x9c\x3e\x8c\x41\x65\x20\x3e\x9e\x36\xca\xc4\x72\x69\xda\xdf\xad\x83\x57\x7d\x1f\xa1\x5a\xf9\x5b\x6b\xdf\x1b\x89\x1d\x06\x1b\x03\x19\x74\x00\x99\x82\x49\xb2\x97\xce\x0e\x3f\x22\x62\xc8\xc7\xd1\x19\xed\x3b\x95\x90\xad\x85\xb7\x19\xfc\x78\xf8\x47\x35\x21\x9e\x90\x08\x3a\xda\xbe\xb7\x97\xd8\x60\x63\x4d\xab\x63\x5b\xfe\x03\x21\xa2\x53\x90\x07\xfb\xfc\x72\x50\x92\x85\xfb\x97\x36\x36\xe8\x46\x4d\xd1\x87\x4e\xfb\xc9\xda\xf4\x81\xa7\xff\x40\x7c\x27\xfb\x1e\x07\x75\x8b\x51\xb5\x31\x65\xf9\xaa\x55\x6b\x14\xac\xc3\x60\x9d\xfa\x69\xe7\xb3\xc9\xcb\xeb\xde\x06\x79\x8d\x1a\x91\x79\xb0\xad\x3e\xea\x38\x42\xb2\x4f\xc3\x98\x45\x3a\xd9\xb6\xa9\xeb\x29\x37\x6d\x40\xce\xd2\x05\xdd\x8c\xbd\x74\x49\xa3\x55\x5e\x9f\x4c\x72\x70\xea\xaf\xe7\xce\x47\x54\x1c\x3e\xd9\x04\xe5\xfc\xcb\x69\xf0\x77\x39\xf2\x42\x0f\x26\xba\x96\xd7\x28\x95\x52\x93\xfd\xcf\x99\x0e\xea\xee\x26\x51\x92\xc4\x39\x83\x88\x99\x15\xd3\x1c\xe0\xa0\x1a\x1b\xaf\x8a\x19\xfb\x3e\x59\x7e\xb6\xba\xc5\xa5\x53\x2f\x6d\x58\x87\xd7\xe3\x8b\x4e\x3e\x47\xac\x32\x77\x5b\x31\xae\x35\xc5\xb2\xe4\x02\x75\xb9\x14\xfb\xbc\xa2\x60\x35\xb6\x55\xf9\x99\x2d\xe8\x02\x0f\x79\x0d\x56\x3f\x64\xd8\x33\xb1\x2e\x77\x02\xfb\xbc\xaa\x72\x2e\x9e\x50\x2e\x91\xf3\x27\x7c\x62\x7c\x91\x81\xd0\xbf\xb7\x15\xad\x6b\x94\x15\xd8\x66\x5b\x30\xba\xc8\xc0\xf8\xbc\xd8\x2d\x18\x5f\xe1\x71\x27\xc0\x4b\x81\x82\x6d\x98\xa0\x0b\x88\x32\x61\x6f\x5c\x8c\xd6\x91\x8d\x6c\x68\x35\x5f\xe7\x5c\xe4\x8f\xac\x60\xe2\x29\xc3\x92\x09\x1e\x49\x97\x65\x85\x1c\xdb\xbc\x12\x6c\xbe\x2b\xf2\x0a\xdb\x5d\xb5\x2d\x6b\x8a\x9c\x2f\xc0\x4b\xce\xf8\xb2\x62\x7c\x45\x37\x94\x0b\x90\x72\x89\x79\xb9\x7d\xaa\xd8\x6a\x2d\x32\x6c\x73\x41\xb9\xc8\x20\xaa\x7c\x41\x37\x79\xf5\x29\x8b\x1e\x4b\xb1\xa6\x15\x52\xc9\x0c\x60\x1c\xbc\x04\xfd\x1c\xe1\xf5\x3a\x2f\x0a\x90\x47\xb6\xa2\x45\xb9\x8f\x5b\xa9\x2c\x36\xb4\x8e\xcd\xcc\x22\xf2\xb6\xf2\x36\x8f\xeb\x19\x1e\x29\x0a\x96\x3f\x16\x74\x72\xc8\x9f\x30\x2f\x72\xb6\xc9\xb0\xc8\x37\xf9\x8a\xd6\x20\x77\xa9\x58\x77\xeb\xea\x5b\x2c\x11\xb1\xa2\x9c\x56\x79\x91\xa1\xde\xd2\x39\x8b\x3f\x18\x5f\xb0\x8a\xce\x45\x06\xc2\xf8\x9c\x2d\x28\x17\xf1\xb8\xac\x30\x2f\x79\x4d\xff\xda\x51\x2e\x58\x5e\x7c\x15\xc9\xb0\x5f\xd3\xa4\xc1\x38\x72\x8e\x7c\x2e\x58\xc9\x53\xa0\xf3\x92\x8b\x2a\x8f\x4c\xa2\xac\xc4\xbd\xef\x3d\xab\x69\x86\xbc\x62\x75\x34\xb1\xac\xca\x4d\x86\xf8\x69\xcb\x25\xe2\x30\xec\x6a\x1a\x4b\x09\xe3\x37\xcb\xf1\x73\xc5\xc3\x1f\x27\xa5\xac\x12\xfc\xd6\xe3\x82\xe6\x05\xe3\xab\x98\xd5\xb7\x5a\xf2\xb5\x78\x46\x08\xfd\xd2\xa8\x73\x80\x8c\xcf\x92\x89\x2b\x7a\xba\xe1\x69\x1d\x4c\x3b\x20\x4b\xf3\x6b\xe4\x30\xdd\xf6\x1f\xde\x06\x92\x1e\xaa\x38\xf1\x2f\x9f\x9e\x69\x6f\xdc\x6e\xf8\xe8\x6f\x7b\xa3\x7d\x56\x2e\x68\x1f\x77\x95\x75\xb0\xa1\x53\xee\xa2\xbd\x4a\xab\xec\xec\xec\x60\x83\x4a\x6a\x5e\xf6\x2a\x8b\xb0\x7b\x59\xda\xf0\xda\x9c\xfc\xdd\xdd\xeb\x7d\x72\xdf\xed\x67\xa7\xad\xc3\xc5\xe9\x10\x94\x81\x1c\x43\x67\x9d\xfe\xef\xf6\x8c\x38\x3b\xfc\xe2\xa1\xfd\xce\x3f\x21\x4b\xeb\xe2\xf6\x4d\xda\xda\x1c\xad\x1b\x12\x47\x36\xe5\xd4\x84\x0f\x84\xc4\xe3\x8f\xa3\xbb\x9c\xcf\xb3\x56\x11\xeb\xc8\xb4\x92\x3e\x1e\x26\x05\x69\xda\x2e\x29\xcc\x1a\x3b\x90\xff\x03\x00\x00\xff\xff\x56\x3f\x10\xa3\x01\x08\x00\x00")
 
 func resources_fonts_copying() ([]byte, error) {
@@ -146,6 +281,21 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() ([]byte, error){
+	"resources/bandplans/aviation.csv": resources_bandplans_aviation_csv,
+	"resources/bandplans/broadcast.csv": resources_bandplans_broadcast_csv,
+	"resources/bandplans/ism.csv": resources_bandplans_ism_csv,
+	"resources/bandplans/itu-r1-amateur.csv": resources_bandplans_itu_r1_amateur_csv,
+	"resources/bandplans/itu-r2-amateur.csv": resources_bandplans_itu_r2_amateur_csv,
+	"resources/bandplans/itu-r3-amateur.csv": resources_bandplans_itu_r3_amateur_csv,
+	"resources/bandplans/marine.csv": resources_bandplans_marine_csv,
+	"resources/bandplans/pmr.csv": resources_bandplans_pmr_csv,
+	"resources/channels/dab.csv": resources_channels_dab_csv,
+	"resources/channels/fm-us.csv": resources_channels_fm_us_csv,
+	"resources/channels/lpd433.csv": resources_channels_lpd433_csv,
+	"resources/channels/pmr446.csv": resources_channels_pmr446_csv,
+	"resources/channels/tv-uhf-eu.csv": resources_channels_tv_uhf_eu_csv,
+	"resources/channels/tv-uhf-us.csv": resources_channels_tv_uhf_us_csv,
+	"resources/channels/wifi24.csv": resources_channels_wifi24_csv,
 	"resources/fonts/COPYING": resources_fonts_copying,
 	"resources/fonts/README": resources_fonts_readme,
 	"resources/fonts/luximr.ttf": resources_fonts_luximr_ttf,
@@ -199,6 +349,40 @@ type _bintree_t struct {
 }
 var _bintree = &_bintree_t{nil, map[string]*_bintree_t{
 	"resources": &_bintree_t{nil, map[string]*_bintree_t{
+		"bandplans": &_bintree_t{nil, map[string]*_bintree_t{
+			"aviation.csv": &_bintree_t{resources_bandplans_aviation_csv, map[string]*_bintree_t{
+			}},
+			"broadcast.csv": &_bintree_t{resources_bandplans_broadcast_csv, map[string]*_bintree_t{
+			}},
+			"ism.csv": &_bintree_t{resources_bandplans_ism_csv, map[string]*_bintree_t{
+			}},
+			"itu-r1-amateur.csv": &_bintree_t{resources_bandplans_itu_r1_amateur_csv, map[string]*_bintree_t{
+			}},
+			"itu-r2-amateur.csv": &_bintree_t{resources_bandplans_itu_r2_amateur_csv, map[string]*_bintree_t{
+			}},
+			"itu-r3-amateur.csv": &_bintree_t{resources_bandplans_itu_r3_amateur_csv, map[string]*_bintree_t{
+			}},
+			"marine.csv": &_bintree_t{resources_bandplans_marine_csv, map[string]*_bintree_t{
+			}},
+			"pmr.csv": &_bintree_t{resources_bandplans_pmr_csv, map[string]*_bintree_t{
+			}},
+		}},
+		"channels": &_bintree_t{nil, map[string]*_bintree_t{
+			"dab.csv": &_bintree_t{resources_channels_dab_csv, map[string]*_bintree_t{
+			}},
+			"fm-us.csv": &_bintree_t{resources_channels_fm_us_csv, map[string]*_bintree_t{
+			}},
+			"lpd433.csv": &_bintree_t{resources_channels_lpd433_csv, map[string]*_bintree_t{
+			}},
+			"pmr446.csv": &_bintree_t{resources_channels_pmr446_csv, map[string]*_bintree_t{
+			}},
+			"tv-uhf-eu.csv": &_bintree_t{resources_channels_tv_uhf_eu_csv, map[string]*_bintree_t{
+			}},
+			"tv-uhf-us.csv": &_bintree_t{resources_channels_tv_uhf_us_csv, map[string]*_bintree_t{
+			}},
+			"wifi24.csv": &_bintree_t{resources_channels_wifi24_csv, map[string]*_bintree_t{
+			}},
+		}},
 		"fonts": &_bintree_t{nil, map[string]*_bintree_t{
 			"COPYING": &_bintree_t{resources_fonts_copying, map[string]*_bintree_t{
 			}},